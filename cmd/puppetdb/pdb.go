@@ -2,7 +2,9 @@ package main
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"strings"
@@ -11,6 +13,7 @@ import (
 	prompt "github.com/c-bata/go-prompt"
 	"github.com/puppetlabs/go-pe-client/internal/cli"
 	"github.com/puppetlabs/go-pe-client/pkg/puppetdb"
+	"github.com/puppetlabs/go-pe-client/pkg/puppetdb/query"
 	"github.com/sirupsen/logrus"
 )
 
@@ -28,6 +31,9 @@ var suggestions = []prompt.Suggest{
 	{Text: "factnames", Description: "Get fact names"},
 	{Text: "inventory", Description: "Get inventory"},
 	{Text: "reports", Description: "Get reports"},
+	{Text: "catalogs", Description: "Get catalogs"},
+	{Text: "resources", Description: "Get resources"},
+	{Text: "query", Description: "Run a PQL or AST query on the root endpoint, e.g. query nodes { certname ~ \"^web\" }"},
 
 	// Binary Operators
 	{Text: "=", Description: "equal to"},
@@ -54,6 +60,16 @@ var suggestions = []prompt.Suggest{
 func executor(in string) {
 	in = strings.TrimSpace(in)
 
+	// query takes a PQL or AST query rather than the api[query] form
+	if strings.HasPrefix(in, "query ") {
+		err := cli.WriteHistory(historyFile, in)
+		if err != nil {
+			logrus.Warnf("Unable to write history to %s because : %s", historyFile.Name(), err)
+		}
+		executeQuery(strings.TrimPrefix(in, "query "))
+		return
+	}
+
 	// Parse the input and extract the API call + query
 	api, query, pagination, orderBy := cli.ParseInput(in)
 	// If a api has been selected, then execute it with the provided query
@@ -83,6 +99,10 @@ func execute(api string, query string, pagination puppetdb.Pagination, orderBy p
 		data, err = client.Inventory(query, &pagination, &orderBy)
 	case "reports":
 		data, err = client.Reports(query, &pagination, &orderBy)
+	case "catalogs":
+		data, err = client.Catalogs(query, &pagination, &orderBy)
+	case "resources":
+		data, err = client.Resources(query, &pagination, &orderBy)
 	case "factnames":
 		data, err = client.FactNames(&pagination, &orderBy)
 	}
@@ -94,6 +114,36 @@ func execute(api string, query string, pagination puppetdb.Pagination, orderBy p
 	cli.PrintString(data)
 }
 
+// executeQuery runs a query on the root endpoint, fetching every page of results
+func executeQuery(in string) {
+	q, err := query.Parse(in)
+	if err != nil {
+		fmt.Println("err: " + err.Error())
+		return
+	}
+	fmt.Printf("Executing Query '%s'\n", q)
+
+	cursor, err := client.PaginatedRootQuery(q.String(), nil, nil)
+	if err != nil {
+		fmt.Println("err: " + err.Error())
+		return
+	}
+	var data []interface{}
+	for {
+		var page []interface{}
+		err = cursor.NextInto(&page)
+		data = append(data, page...)
+		if err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, io.EOF) {
+		fmt.Println("err: " + err.Error())
+		return
+	}
+	cli.PrintString(data)
+}
+
 func completer(in prompt.Document) []prompt.Suggest {
 	w := in.GetWordBeforeCursor()
 	if w == "" {
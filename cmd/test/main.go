@@ -12,8 +12,9 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/puppetlabs/go-pe-client/pkg/classifier"
 	"github.com/puppetlabs/go-pe-client/pkg/orch"
-	"github.com/puppetlabs/go-pe-client/pkg/pe"
+	"github.com/puppetlabs/go-pe-client/pkg/peclient"
 	"github.com/puppetlabs/go-pe-client/pkg/puppetdb"
+	"github.com/puppetlabs/go-pe-client/pkg/puppetdb/query"
 	"github.com/puppetlabs/go-pe-client/pkg/rbac"
 )
 
@@ -121,20 +122,18 @@ Get the RBAC token: go run cmd/test/main.go <pe-server> <login> <password> e.g.
 	peServer := os.Args[1]
 	token := os.Args[2]
 
-	pdbHostURL := "https://" + peServer + ":8081"
-	pdbClient := puppetdb.NewClient(pdbHostURL, token, &tls.Config{InsecureSkipVerify: true}, pdbTimeout) // #nosec - this main() is private and for development purpose
-
-	orchHostURL := "https://" + peServer + ":8143"
-	orchClient := orch.NewClient(orchHostURL, token, &tls.Config{InsecureSkipVerify: true}) // #nosec - this main() is private and for development purpose
-
-	classifierHostURL := "https://" + peServer + ":4433"
-	classifierClient := classifier.NewClient(classifierHostURL, token, &tls.Config{InsecureSkipVerify: true}) // #nosec - this main() is private and for development purpose
-
-	peHostURL := "https://" + peServer
-	peClient := pe.NewClient(peHostURL, token, &tls.Config{InsecureSkipVerify: true}) // #nosec - this main() is private and for development purpose
-
-	rbacHostURL := "https://" + peServer + ":4433"
-	rbacClient := rbac.NewClient(rbacHostURL, &tls.Config{InsecureSkipVerify: true}) // #nosec - this main() is private and for development purpose
+	peInstall, err := peclient.New(peServer, nil, token, &peclient.Options{
+		TLSConfig:       &tls.Config{InsecureSkipVerify: true}, // #nosec - this main() is private and for development purpose
+		PuppetDBTimeout: pdbTimeout,
+	})
+	if err != nil {
+		panic(err)
+	}
+	pdbClient := peInstall.PuppetDB()
+	orchClient := peInstall.Orch()
+	classifierClient := peInstall.Classifier()
+	peClient := peInstall.Console()
+	rbacClient := peInstall.RBAC()
 
 	fmt.Printf("Connecting to: %s\n\n", peServer)
 
@@ -185,7 +184,7 @@ Get the RBAC token: go run cmd/test/main.go <pe-server> <login> <password> e.g.
 	var role *rbac.Role
 
 	createdRoleID, _ := strconv.Atoi(createdRoleIDString)
-	role, err := rbacClient.GetRole(uint(createdRoleID), token)
+	role, err = rbacClient.GetRole(uint(createdRoleID), token)
 	if err != nil {
 		panic(err)
 	}
@@ -235,7 +234,7 @@ Get the RBAC token: go run cmd/test/main.go <pe-server> <login> <password> e.g.
 	spew.Dump(nodes)
 	fmt.Println()
 
-	nodes, err = pdbClient.Nodes(fmt.Sprintf(`["=", "certname", "%s"]`, peServer), nil, nil)
+	nodes, err = pdbClient.Nodes(query.Equal("certname", peServer).String(), nil, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,163 @@
+// Package apierror describes the errors returned by the PE APIs the same way for every client, e.g.
+//
+//	var apiErr *apierror.Error
+//	if errors.As(err, &apiErr) {
+//		http.Error(w, apiErr.Msg, apiErr.StatusCode)
+//	}
+//
+// The client packages return their own error types, which can all be converted with errors.As.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+)
+
+// Services that return errors
+const (
+	ServiceOrchestrator = "orchestrator"
+	ServicePuppetDB     = "puppetdb"
+	ServiceClassifier   = "classifier"
+	ServiceRBAC         = "rbac"
+	ServiceConsole      = "console"
+)
+
+// Errors to match with errors.Is, each matches any Error with its status code
+var (
+	ErrBadRequest   = &Error{StatusCode: http.StatusBadRequest}
+	ErrUnauthorized = &Error{StatusCode: http.StatusUnauthorized}
+	ErrForbidden    = &Error{StatusCode: http.StatusForbidden}
+	ErrNotFound     = &Error{StatusCode: http.StatusNotFound}
+	ErrConflict     = &Error{StatusCode: http.StatusConflict}
+)
+
+// Error is an error response from a PE API
+// Service	The service that responded, one of the Service constants.
+// StatusCode	The HTTP status of the response.
+// Kind	The PE error kind, e.g. puppetlabs.rbac/user-not-found, if the response had one.
+// Msg	The message of the response, or its body if it was not a PE error.
+// Details	The details of the response, if it had any.
+// Path	The path of the request.
+// Err	The error of the client package the Error was made for, e.g. puppetdb.ErrTransientResponse.
+type Error struct {
+	Service    string
+	StatusCode int
+	Kind       string
+	Msg        string
+	Details    interface{}
+	Path       string
+	Err        error
+}
+
+// FromResponse creates the Error for an error response of service, taking the kind, message and details
+// from the body if it is a PE error
+func FromResponse(service string, r *resty.Response) *Error {
+	return FromBody(service, r, r.Body())
+}
+
+// FromBody is FromResponse for a response whose body resty did not read, such as a streamed one
+func FromBody(service string, r *resty.Response, responseBody []byte) *Error {
+	e := &Error{Service: service, StatusCode: r.StatusCode(), Path: RequestPath(r)}
+
+	var body struct {
+		Kind    string      `json:"kind"`
+		Msg     string      `json:"msg"`
+		Details interface{} `json:"details"`
+	}
+	if err := json.Unmarshal(responseBody, &body); err == nil && (body.Kind != "" || body.Msg != "") {
+		e.Kind, e.Msg, e.Details = body.Kind, body.Msg, body.Details
+	} else {
+		e.Msg = strings.TrimSpace(string(responseBody))
+	}
+	return e
+}
+
+// RequestPath returns the path of the request r is the response to
+func RequestPath(r *resty.Response) string {
+	if r.RawResponse != nil && r.RawResponse.Request != nil {
+		return r.RawResponse.Request.URL.Path
+	}
+	if r.Request == nil {
+		return ""
+	}
+	// The URL of the request may still be the full URL if the response did not keep its request
+	if u, err := url.Parse(r.Request.URL); err == nil && u.Path != "" {
+		return u.Path
+	}
+	return r.Request.URL
+}
+
+// Error returns the message, or the status if there is no message, followed by the wrapped error
+func (e *Error) Error() string {
+	msg := e.Msg
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the error of the client package
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches an Error target whose non-zero Service, StatusCode and Kind are the same as e's,
+// so that errors.Is(err, ErrNotFound) matches any not found response
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || (t.Service == "" && t.StatusCode == 0 && t.Kind == "") {
+		return false
+	}
+	return (t.Service == "" || t.Service == e.Service) &&
+		(t.StatusCode == 0 || t.StatusCode == e.StatusCode) &&
+		(t.Kind == "" || t.Kind == e.Kind)
+}
+
+// StatusCode returns the HTTP status of the API error in err, or 0 if err is not an API error
+func StatusCode(err error) int {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is a 404 response
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+// IsUnauthorized reports whether err is a 401 response, e.g. for an expired token
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// IsForbidden reports whether err is a 403 response
+func IsForbidden(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+// IsConflict reports whether err is a 409 response
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+// IsTransient reports whether the request that failed with err is likely to succeed if it is sent again,
+// either because the response has one of the statuses the retry package retries or because of a network timeout
+func IsTransient(err error) bool {
+	if status := StatusCode(err); status != 0 {
+		return retry.IsTransientStatus(status)
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,101 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+var errPackage = errors.New("package error")
+
+func get(t *testing.T, status int, body string) *resty.Response {
+	client := resty.New()
+	httpmock.ActivateNonDefault(client.GetClient())
+	t.Cleanup(httpmock.DeactivateAndReset)
+	httpmock.RegisterResponder(http.MethodGet, "https://pe.example.com:4433/classifier/v1/groups/abc",
+		httpmock.NewStringResponder(status, body))
+
+	r, err := client.R().SetPathParam("id", "abc").Get("https://pe.example.com:4433/classifier/v1/groups/{id}")
+	require.Nil(t, err)
+	return r
+}
+
+func TestFromResponse(t *testing.T) {
+	// Test PE error
+	r := get(t, http.StatusNotFound, `{"kind": "not-found", "msg": "no group abc", "details": {"id": "abc"}}`)
+	require.Equal(t, &Error{
+		Service:    ServiceClassifier,
+		StatusCode: http.StatusNotFound,
+		Kind:       "not-found",
+		Msg:        "no group abc",
+		Details:    map[string]interface{}{"id": "abc"},
+		Path:       "/classifier/v1/groups/abc",
+	}, FromResponse(ServiceClassifier, r))
+
+	// Test other bodies
+	r = get(t, http.StatusBadGateway, "bad gateway\n")
+	require.Equal(t, &Error{
+		Service:    ServicePuppetDB,
+		StatusCode: http.StatusBadGateway,
+		Msg:        "bad gateway",
+		Path:       "/classifier/v1/groups/abc",
+	}, FromResponse(ServicePuppetDB, r))
+
+	r = get(t, http.StatusInternalServerError, `["not", "an", "error"]`)
+	require.Equal(t, `["not", "an", "error"]`, FromResponse(ServicePuppetDB, r).Msg)
+
+	// Test a body read separately
+	e := FromBody(ServicePuppetDB, r, []byte(`{"kind": "query-error", "msg": "bad query"}`))
+	require.Equal(t, http.StatusInternalServerError, e.StatusCode)
+	require.Equal(t, "query-error", e.Kind)
+	require.Equal(t, "bad query", e.Msg)
+	require.Equal(t, "/classifier/v1/groups/abc", e.Path)
+}
+
+func TestError(t *testing.T) {
+	require.EqualError(t, &Error{StatusCode: http.StatusForbidden, Msg: "permission denied"}, "permission denied")
+	require.EqualError(t, &Error{StatusCode: http.StatusForbidden}, "Forbidden")
+	require.EqualError(t, &Error{StatusCode: http.StatusBadGateway, Err: errPackage}, "Bad Gateway: package error")
+
+	err := fmt.Errorf("request failed: %w", &Error{Service: ServiceRBAC, StatusCode: http.StatusNotFound, Kind: "not-found", Err: errPackage})
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, ServiceRBAC, apiErr.Service)
+	require.ErrorIs(t, err, errPackage)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.ErrorIs(t, err, &Error{Service: ServiceRBAC, Kind: "not-found"})
+	require.NotErrorIs(t, err, ErrConflict)
+	require.NotErrorIs(t, err, &Error{Service: ServiceOrchestrator, StatusCode: http.StatusNotFound})
+	require.NotErrorIs(t, err, &Error{})
+}
+
+func TestHelpers(t *testing.T) {
+	for status, is := range map[int]func(error) bool{
+		http.StatusNotFound:     IsNotFound,
+		http.StatusUnauthorized: IsUnauthorized,
+		http.StatusForbidden:    IsForbidden,
+		http.StatusConflict:     IsConflict,
+	} {
+		err := fmt.Errorf("wrapped: %w", &Error{StatusCode: status})
+		require.True(t, is(err), status)
+		require.False(t, is(&Error{StatusCode: http.StatusInternalServerError}), status)
+		require.False(t, is(errPackage), status)
+		require.Equal(t, status, StatusCode(err))
+	}
+	require.Equal(t, 0, StatusCode(errPackage))
+
+	require.True(t, IsTransient(&Error{StatusCode: http.StatusServiceUnavailable}))
+	require.False(t, IsTransient(&Error{StatusCode: http.StatusInternalServerError}))
+	require.False(t, IsTransient(context.Canceled))
+
+	// Test network timeout
+	client := resty.New().SetTimeout(1)
+	_, err := client.R().Get("https://192.0.2.1/")
+	require.True(t, IsTransient(err))
+}
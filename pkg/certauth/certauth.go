@@ -0,0 +1,141 @@
+// Package certauth authenticates the PE API clients with a client certificate instead of a token.
+// Pass the TLS config to a client constructor along with an empty token, e.g.
+//
+//	tlsConfig, err := certauth.NewTLSConfig(certauth.Config{CertFile: cert, KeyFile: key, CAFile: ca})
+//	client := puppetdb.NewClient("https://puppetdb.example.com:8081", "", tlsConfig, timeout)
+//
+// The certificate must be allowed by the service, e.g. in the PuppetDB certificate allowlist.
+package certauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrInvalidCA is returned when the CA file has no PEM encoded certificates
+var ErrInvalidCA = errors.New("certauth: no certificates found in the CA file")
+
+// Config names the PEM files to authenticate with
+// CertFile	The client certificate, which may be followed by intermediate certificates.
+// KeyFile	The private key of the client certificate.
+// CAFile	The CA bundle to verify the server with, empty to use the system roots.
+type Config struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
+}
+
+// NewTLSConfig loads the CA bundle and client certificate of the config into a TLS config. The client
+// certificate is reloaded when its files change, see Reloader.
+func NewTLSConfig(config Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CAFile != "" {
+		pool, err := LoadCAFile(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	reloader, err := NewReloader(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+	return tlsConfig, nil
+}
+
+// LoadCAFile reads a PEM encoded CA bundle into a certificate pool
+func LoadCAFile(caFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(filepath.Clean(caFile))
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCA, caFile)
+	}
+	return pool, nil
+}
+
+// Reloader holds a client certificate loaded from PEM files and loads it again when the files are
+// modified, so that a certificate rotated on disk is used without restarting. The files are checked
+// when a TLS connection is made, connections that are already open keep the certificate they started with.
+// If the files cannot be loaded, e.g. because only one of the pair has been replaced so far, the last
+// good certificate is kept.
+type Reloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// NewReloader loads the certificate and key, returning an error if they cannot be loaded
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the certificate and key from their files
+func (r *Reloader) Reload() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("certauth: loading %s and %s: %w", r.certFile, r.keyFile, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.modTime = modTime
+	return nil
+}
+
+// Certificate returns the current certificate, reloading it first if its files have been modified
+func (r *Reloader) Certificate() *tls.Certificate {
+	if modTime, err := r.latestModTime(); err == nil {
+		r.mu.Lock()
+		changed := modTime.After(r.modTime)
+		r.mu.Unlock()
+		if changed {
+			_ = r.Reload()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cert
+}
+
+// GetClientCertificate returns the current certificate, it is meant for tls.Config.GetClientCertificate
+func (r *Reloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.Certificate(), nil
+}
+
+// latestModTime returns the most recent modification time of the certificate and key files
+func (r *Reloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,130 @@
+package certauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "agent")
+	caFile, _ := writeCertificate(t, dir, "ca")
+
+	tlsConfig, err := NewTLSConfig(Config{CertFile: certFile, KeyFile: keyFile, CAFile: caFile})
+	require.Nil(t, err)
+	require.NotNil(t, tlsConfig.RootCAs)
+	require.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+
+	cert, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.Nil(t, err)
+	require.Equal(t, "agent", commonName(t, cert))
+
+	// Test without a CA file
+	tlsConfig, err = NewTLSConfig(Config{CertFile: certFile, KeyFile: keyFile})
+	require.Nil(t, err)
+	require.Nil(t, tlsConfig.RootCAs)
+
+	// Test missing certificate
+	_, err = NewTLSConfig(Config{CertFile: filepath.Join(dir, "missing.pem"), KeyFile: keyFile})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadCAFile(t *testing.T) {
+	dir := t.TempDir()
+	caFile, keyFile := writeCertificate(t, dir, "ca")
+
+	pool, err := LoadCAFile(caFile)
+	require.Nil(t, err)
+	require.NotNil(t, pool)
+
+	// Test a file without certificates
+	_, err = LoadCAFile(keyFile)
+	require.ErrorIs(t, err, ErrInvalidCA)
+
+	// Test missing file
+	_, err = LoadCAFile(filepath.Join(dir, "missing.pem"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "agent")
+
+	reloader, err := NewReloader(certFile, keyFile)
+	require.Nil(t, err)
+	require.Equal(t, "agent", commonName(t, reloader.Certificate()))
+
+	// Test the rotated certificate is loaded
+	rotate(t, dir, "agent", "rotated")
+	require.Equal(t, "rotated", commonName(t, reloader.Certificate()))
+
+	// Test the last good certificate is kept when the files cannot be loaded
+	require.Nil(t, os.WriteFile(keyFile, []byte("not a key"), 0600))
+	touch(t, keyFile, time.Now().Add(2*time.Hour))
+	require.Equal(t, "rotated", commonName(t, reloader.Certificate()))
+	require.NotNil(t, reloader.Reload())
+
+	// Test missing key
+	_, err = NewReloader(certFile, filepath.Join(dir, "missing.pem"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// rotate replaces the certificate and key of name with a new pair for commonName, dated in the future so
+// the change is seen regardless of the file system's timestamp resolution
+func rotate(t *testing.T, dir, name, commonName string) {
+	rotated, rotatedKey := writeCertificate(t, t.TempDir(), commonName)
+	for from, to := range map[string]string{rotated: name + ".pem", rotatedKey: name + "-key.pem"} {
+		data, err := os.ReadFile(from)
+		require.Nil(t, err)
+		path := filepath.Join(dir, to)
+		require.Nil(t, os.WriteFile(path, data, 0600))
+		touch(t, path, time.Now().Add(time.Hour))
+	}
+}
+
+func touch(t *testing.T, path string, modTime time.Time) {
+	require.Nil(t, os.Chtimes(path, modTime, modTime))
+}
+
+// writeCertificate writes a self-signed certificate for commonName and its key to dir
+func writeCertificate(t *testing.T, dir, commonName string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err)
+
+	certFile := filepath.Join(dir, commonName+".pem")
+	keyFile := filepath.Join(dir, commonName+"-key.pem")
+	require.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func commonName(t *testing.T, cert *tls.Certificate) string {
+	require.NotNil(t, cert)
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	require.Nil(t, err)
+	return parsed.Subject.CommonName
+}
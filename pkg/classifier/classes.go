@@ -1,12 +1,19 @@
 package classifier
 
+import "context"
+
 const (
 	classes = "/classifier-api/v1/classes"
 )
 
 func (c *Client) Classes(pagination *Pagination) ([]Class, error) {
+	return c.ClassesCtx(context.Background(), pagination)
+}
+
+// ClassesCtx is Classes with a context that cancels the request when done
+func (c *Client) ClassesCtx(ctx context.Context, pagination *Pagination) ([]Class, error) {
 	payload := []Class{}
-	err := getRequest(c, classes, nil, &payload)
+	err := getRequest(ctx, c, classes, nil, &payload)
 	return payload, err
 }
 
@@ -1,6 +1,7 @@
 package classifier
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -8,21 +9,28 @@ import (
 	"net/url"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/puppetlabs/go-pe-client/pkg/tokenauth"
 )
 
 // Client for the Orchestrator API
 type Client struct {
-	resty *resty.Client
+	resty       *resty.Client
+	tokenSource tokenauth.TokenSource
 }
 
-// NewClient access the orchestrator API via TLS
+// NewClient access the orchestrator API via TLS. If token is empty the requests carry no X-Authentication
+// header and rely on the client certificate in tlsConfig.
 func NewClient(hostURL, token string, tlsConfig *tls.Config) *Client {
 	r := resty.New()
 	if tlsConfig != nil {
 		r.SetTLSClientConfig(tlsConfig)
 	}
 	r.SetBaseURL(hostURL)
-	r.SetHeader("X-Authentication", token)
+	if token != "" {
+		r.SetHeader("X-Authentication", token)
+	}
 	r.SetRedirectPolicy(resty.NoRedirectPolicy())
 
 	return &Client{resty: r}
@@ -31,18 +39,35 @@ func NewClient(hostURL, token string, tlsConfig *tls.Config) *Client {
 // SetTransport lets the caller overwrite the default transport used by the client.
 // This is useful when injecting mock transports for testing purposes.
 func (c *Client) SetTransport(tripper http.RoundTripper) {
+	if c.tokenSource != nil {
+		tripper = tokenauth.NewTransport(tripper, c.tokenSource)
+	}
 	c.resty.SetTransport(tripper)
 }
 
+// SetRetryPolicy sets how the client retries requests that fail with a transient error.
+// The client does not retry unless a policy is set, e.g. retry.DefaultPolicy().
+func (c *Client) SetRetryPolicy(policy retry.Policy) {
+	policy.Apply(c.resty)
+}
+
+// SetTokenSource makes the client take the X-Authentication token of each request from source instead of
+// the token given to NewClient. A request rejected with a 401 is sent once more with a refreshed token.
+func (c *Client) SetTokenSource(source tokenauth.TokenSource) {
+	c.tokenSource = source
+	c.resty.SetTransport(tokenauth.NewTransport(c.resty.GetClient().Transport, source))
+}
+
 // getRequest uses the Given client to make a HTTP GET request to the given path, providing
-// the query.  The result of the request is marshalled into the response type. e.g.
+// the query. The request is cancelled when ctx is done. The result of the request is marshalled
+// into the response type. e.g.
 // var payload *[]Group
-// getRequest(client, "/classifier/v1/groups",
+// getRequest(ctx, client, "/classifier/v1/groups",
 //
 //	&Pagination{Limit: 10, Offset: 20},
 //	&payload)
-func getRequest(client *Client, path string, pagination *Pagination, response interface{}) error {
-	req := client.resty.R().SetResult(&response)
+func getRequest(ctx context.Context, client *Client, path string, pagination *Pagination, response interface{}) error {
+	req := client.resty.R().SetContext(ctx).SetResult(&response)
 
 	if pagination != nil {
 		req.SetQueryParams(pagination.toParams())
@@ -57,21 +82,19 @@ func getRequest(client *Client, path string, pagination *Pagination, response in
 		return fmt.Errorf("%s%s: %w", client.resty.HostURL, path, err)
 	}
 	if r.IsError() {
-		re := r.Error()
-		if re == nil {
-			return fmt.Errorf("%s%s: %s: \"%s\"", client.resty.HostURL, path, r.Status(), r.Body())
-		}
-		return fmt.Errorf("%s%s: %s: \"%s\": %v", client.resty.HostURL, path, r.Status(), r.Body(), re)
+		return responseError(client, path, r)
 	}
 
 	return nil
 }
 
-func postRequest(client *Client, path string, body string, response interface{}) error {
+func postRequest(ctx context.Context, client *Client, path string, body string, response interface{}) error {
 	req := client.resty.R().
+		SetContext(ctx).
 		SetResult(&response).
 		SetHeader("Content-Type", "application/json").
-		SetBody(body)
+		SetBody(body).
+		AddRetryCondition(retry.Transient)
 
 	r, err := req.Post(path)
 	if err != nil {
@@ -82,19 +105,31 @@ func postRequest(client *Client, path string, body string, response interface{})
 		return fmt.Errorf("%s%s: %w", client.resty.HostURL, path, err)
 	}
 	if r.IsError() {
-		re := r.Error()
-		if re == nil {
-			return fmt.Errorf("%s%s: %s: \"%s\"", client.resty.HostURL, path, r.Status(), r.Body())
-		}
-		return fmt.Errorf("%s%s: %s: \"%s\": %v", client.resty.HostURL, path, r.Status(), r.Body(), re)
+		return responseError(client, path, r)
 	}
 
 	return nil
 }
 
+// responseError describes the error response r to a request for path. The *apierror.Error it wraps
+// carries the response's error, if it has one.
+func responseError(client *Client, path string, r *resty.Response) error {
+	apiErr := apierror.FromResponse(apierror.ServiceClassifier, r)
+	if re, ok := r.Error().(error); ok {
+		apiErr.Err = re
+	}
+	return fmt.Errorf("%s%s: %s: %w", client.resty.HostURL, path, r.Status(), apiErr)
+}
+
 // PostRequest posts a request to the specified uri
 func PostRequest(client *Client, uri string) ([]byte, error) {
+	return PostRequestCtx(context.Background(), client, uri)
+}
+
+// PostRequestCtx is PostRequest with a context that cancels the request when done
+func PostRequestCtx(ctx context.Context, client *Client, uri string) ([]byte, error) {
 	r, err := client.resty.R().
+		SetContext(ctx).
 		Post(uri)
 	if err != nil {
 		return nil, err
@@ -103,7 +138,7 @@ func PostRequest(client *Client, uri string) ([]byte, error) {
 		if r.Error() != nil {
 			return nil, r.Error().(error)
 		}
-		return nil, fmt.Errorf("%s error: %s", uri, r.Status())
+		return nil, fmt.Errorf("%s error: %s: %w", uri, r.Status(), apierror.FromResponse(apierror.ServiceClassifier, r))
 	}
 
 	return r.Body(), nil
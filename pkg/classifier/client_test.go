@@ -0,0 +1,36 @@
+package classifier
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRetryPolicy(t *testing.T) {
+	pdbClient.SetRetryPolicy(retry.Policy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: time.Millisecond})
+	defer pdbClient.SetRetryPolicy(retry.Policy{})
+
+	// Test GET is retried
+	calls := setupUnavailableResponder(t, groups, "groups-response.json")
+	actual, err := pdbClient.Groups(nil)
+	require.Nil(t, err)
+	require.Equal(t, expected, actual)
+	require.Equal(t, 2, calls())
+
+	// Test read only POST is retried
+	calls = setupUnavailableResponder(t, rules, "rules-translate-response.json")
+	query, err := pdbClient.TranslateRules(`["=", "name", "foo"]`)
+	require.Nil(t, err)
+	require.Equal(t, `["=","certname","foo"]`, query)
+	require.Equal(t, 2, calls())
+
+	// Test any other POST is not retried
+	calls = setupUnavailableResponder(t, uri+"/foo", "group.json")
+	_, err = PostRequest(pdbClient, uri+"/foo")
+	require.Equal(t, http.StatusServiceUnavailable, apierror.StatusCode(err))
+	require.Equal(t, 1, calls())
+}
@@ -1,6 +1,7 @@
 package classifier
 
 import (
+	"context"
 	"strings"
 )
 
@@ -10,9 +11,14 @@ const (
 
 // GroupRules will return the rules for the specified group.
 func (c *Client) GroupRules(groupID string) (GroupRules, error) {
+	return c.GroupRulesCtx(context.Background(), groupID)
+}
+
+// GroupRulesCtx is GroupRules with a context that cancels the request when done
+func (c *Client) GroupRulesCtx(ctx context.Context, groupID string) (GroupRules, error) {
 	var payload GroupRules
 	path := strings.ReplaceAll(groupRulesPathTemplate, "{group-id}", groupID)
-	err := getRequest(c, path, nil, &payload)
+	err := getRequest(ctx, c, path, nil, &payload)
 
 	return payload, err
 }
@@ -1,6 +1,7 @@
 package classifier
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -11,15 +12,25 @@ const (
 
 // Groups will return all groups.
 func (c *Client) Groups(pagination *Pagination) ([]Group, error) {
+	return c.GroupsCtx(context.Background(), pagination)
+}
+
+// GroupsCtx is Groups with a context that cancels the request when done
+func (c *Client) GroupsCtx(ctx context.Context, pagination *Pagination) ([]Group, error) {
 	payload := []Group{}
-	err := getRequest(c, groups, pagination, &payload)
+	err := getRequest(ctx, c, groups, pagination, &payload)
 	return payload, err
 }
 
 // Group will return the group matching the given id.
 func (c *Client) Group(id string) (Group, error) {
+	return c.GroupCtx(context.Background(), id)
+}
+
+// GroupCtx is Group with a context that cancels the request when done
+func (c *Client) GroupCtx(ctx context.Context, id string) (Group, error) {
 	payload := Group{}
-	err := getRequest(c, fmt.Sprintf("%s/%s", groups, id), nil, &payload)
+	err := getRequest(ctx, c, fmt.Sprintf("%s/%s", groups, id), nil, &payload)
 	return payload, err
 }
 
@@ -1,6 +1,8 @@
 package classifier
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -8,6 +10,7 @@ import (
 	"time"
 
 	"github.com/jarcoal/httpmock"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -25,12 +28,44 @@ func TestGroups(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestGroupsCtx(t *testing.T) {
+	setupGetResponder(t, groups, "", "groups-response.json")
+	actual, err := pdbClient.GroupsCtx(context.Background(), nil)
+	require.Nil(t, err)
+	require.Equal(t, expected, actual)
+
+	setupBlockingResponder(groups)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pdbClient.GroupsCtx(ctx, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	setupBlockingResponder(uri + "/node1")
+	_, err = pdbClient.NodeCtx(ctx, "node1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestGroup(t *testing.T) {
 	setupGetResponder(t, fmt.Sprintf("%s/%s", groups, g2ID), "", "group.json")
 	actual, err := pdbClient.Group(g2ID)
 	require.Nil(t, err)
 	require.NotNil(t, actual)
 	require.Equal(t, g2, actual)
+
+	// Test error
+	setupResponderWithStatusCodeAndBody(t, fmt.Sprintf("%s/%s", groups, g2ID), http.StatusNotFound,
+		map[string]string{"kind": "not-found", "msg": "The group could not be found"})
+	_, err = pdbClient.Group(g2ID)
+	require.EqualError(t, err, hostURL+groups+"/"+g2ID+": 404: The group could not be found")
+	var apiErr *apierror.Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, &apierror.Error{
+		Service:    apierror.ServiceClassifier,
+		StatusCode: http.StatusNotFound,
+		Kind:       "not-found",
+		Msg:        "The group could not be found",
+		Path:       groups + "/" + g2ID,
+	}, apiErr)
 }
 
 func setupGetResponder(t *testing.T, url, query, responseFilename string) {
@@ -55,6 +90,39 @@ func setupResponderWithStatusCodeAndBody(t *testing.T, url string, statusCode in
 	httpmock.RegisterResponder(http.MethodPost, hostURL+url, responder)
 }
 
+// setupBlockingResponder responds to every request for url only once the request's context is done
+func setupBlockingResponder(url string) {
+	httpmock.Reset()
+	responder := func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	httpmock.RegisterResponder(http.MethodGet, hostURL+url, responder)
+	httpmock.RegisterResponder(http.MethodPost, hostURL+url, responder)
+}
+
+// setupUnavailableResponder responds to GET and POST requests for url with a 503 the first time
+// and the given response file after that. It returns a func counting the calls made.
+func setupUnavailableResponder(t *testing.T, url, responseFilename string) func() int {
+	httpmock.Reset()
+	responseBody, err := os.ReadFile("testdata/" + responseFilename)
+	require.Nil(t, err)
+
+	calls := 0
+	responder := func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, `{}`), nil
+		}
+		response := httpmock.NewBytesResponse(http.StatusOK, responseBody)
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	}
+	httpmock.RegisterResponder(http.MethodGet, hostURL+url, responder)
+	httpmock.RegisterResponder(http.MethodPost, hostURL+url, responder)
+	return func() int { return calls }
+}
+
 var (
 	pdbClient *Client
 	hostURL   = "https://test-host:4433"
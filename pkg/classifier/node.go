@@ -1,6 +1,7 @@
 package classifier
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -12,7 +13,12 @@ const (
 // Node will return the Node matching the given id.
 // certname is the hostname of the node to query.
 func (c *Client) Node(certname string) (Node, error) {
-	payload, err := PostRequest(c, fmt.Sprintf("%s/%s", uri, certname))
+	return c.NodeCtx(context.Background(), certname)
+}
+
+// NodeCtx is Node with a context that cancels the request when done
+func (c *Client) NodeCtx(ctx context.Context, certname string) (Node, error) {
+	payload, err := PostRequestCtx(ctx, c, fmt.Sprintf("%s/%s", uri, certname))
 	if err != nil {
 		return Node{}, err
 	}
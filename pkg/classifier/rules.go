@@ -1,6 +1,7 @@
 package classifier
 
 import (
+	"context"
 	"encoding/json"
 )
 
@@ -10,8 +11,13 @@ const (
 
 // TranslateRules converts a group's rule condition into PuppetDB query syntax.
 func (c *Client) TranslateRules(rule string) (string, error) {
+	return c.TranslateRulesCtx(context.Background(), rule)
+}
+
+// TranslateRulesCtx is TranslateRules with a context that cancels the request when done
+func (c *Client) TranslateRulesCtx(ctx context.Context, rule string) (string, error) {
 	var payload Rule
-	err := postRequest(c, rules, rule, &payload)
+	err := postRequest(ctx, c, rules, rule, &payload)
 	if err != nil {
 		return "", err
 	}
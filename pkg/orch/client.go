@@ -4,24 +4,32 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"net/http"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/puppetlabs/go-pe-client/pkg/tokenauth"
 )
 
 // Client for the Orchestrator API
 type Client struct {
-	resty  *resty.Client
-	strict bool
+	resty       *resty.Client
+	strict      bool
+	tokenSource tokenauth.TokenSource
 }
 
-// NewClient access the orchestrator API via TLS
+// NewClient access the orchestrator API via TLS. With an empty token no X-Authentication header is sent,
+// e.g. when authenticating with a client certificate from certauth.
 func NewClient(hostURL string, token string, tlsConfig *tls.Config) *Client {
 	r := resty.New()
 	if tlsConfig != nil {
 		r.SetTLSClientConfig(tlsConfig)
 	}
 	r.SetBaseURL(hostURL)
-	r.SetHeader("X-Authentication", token)
+	if token != "" {
+		r.SetHeader("X-Authentication", token)
+	}
 	r.SetError(OrchestratorError{})
 	r.SetRedirectPolicy(resty.NoRedirectPolicy())
 
@@ -36,11 +44,34 @@ func NewClient(hostURL string, token string, tlsConfig *tls.Config) *Client {
 	return &client
 }
 
+// SetTransport lets the caller overwrite the default transport used by the client.
+// This is useful when injecting mock transports for testing purposes.
+func (c *Client) SetTransport(tripper http.RoundTripper) {
+	if c.tokenSource != nil {
+		tripper = tokenauth.NewTransport(tripper, c.tokenSource)
+	}
+	c.resty.SetTransport(tripper)
+}
+
+// SetRetryPolicy sets how the client retries requests that fail with a transient error.
+// The client does not retry unless a policy is set, e.g. retry.DefaultPolicy().
+func (c *Client) SetRetryPolicy(policy retry.Policy) {
+	policy.Apply(c.resty)
+}
+
+// SetTokenSource makes the client take the X-Authentication token of each request from source instead of
+// the token given to NewClient. A request rejected with a 401 is sent once more with a refreshed token.
+func (c *Client) SetTokenSource(source tokenauth.TokenSource) {
+	c.tokenSource = source
+	c.resty.SetTransport(tokenauth.NewTransport(c.resty.GetClient().Transport, source))
+}
+
 // OrchestratorError represents an error response from the Orchestrator API
 type OrchestratorError struct {
 	Kind       string `json:"kind"`
 	Msg        string `json:"Msg"`
 	StatusCode int
+	Path       string `json:"-"`
 }
 
 func (oe *OrchestratorError) Error() string {
@@ -52,10 +83,29 @@ func (oe *OrchestratorError) GetStatusCode() int {
 	return oe.StatusCode
 }
 
+// As converts the error to an *apierror.Error
+func (oe *OrchestratorError) As(target interface{}) bool {
+	if e, ok := target.(**apierror.Error); ok {
+		*e = oe.apiError()
+		return true
+	}
+	return false
+}
+
+// Is matches an *apierror.Error target such as apierror.ErrNotFound
+func (oe *OrchestratorError) Is(target error) bool {
+	return oe.apiError().Is(target)
+}
+
+func (oe *OrchestratorError) apiError() *apierror.Error {
+	return &apierror.Error{Service: apierror.ServiceOrchestrator, StatusCode: oe.StatusCode, Kind: oe.Kind, Msg: oe.Msg, Path: oe.Path}
+}
+
 // HTTPError represents an error with the HTTP response code
 type HTTPError struct {
 	Msg        string
 	StatusCode int
+	Path       string
 }
 
 func (he *HTTPError) Error() string {
@@ -66,3 +116,21 @@ func (he *HTTPError) Error() string {
 func (he *HTTPError) GetStatusCode() int {
 	return he.StatusCode
 }
+
+// As converts the error to an *apierror.Error
+func (he *HTTPError) As(target interface{}) bool {
+	if e, ok := target.(**apierror.Error); ok {
+		*e = he.apiError()
+		return true
+	}
+	return false
+}
+
+// Is matches an *apierror.Error target such as apierror.ErrNotFound
+func (he *HTTPError) Is(target error) bool {
+	return he.apiError().Is(target)
+}
+
+func (he *HTTPError) apiError() *apierror.Error {
+	return &apierror.Error{Service: apierror.ServiceOrchestrator, StatusCode: he.StatusCode, Msg: he.Msg, Path: he.Path}
+}
@@ -0,0 +1,57 @@
+package orch
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/puppetlabs/go-pe-client/pkg/tokenauth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRetryPolicy(t *testing.T) {
+	orchClient.SetRetryPolicy(retry.Policy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: time.Millisecond})
+	defer orchClient.SetRetryPolicy(retry.Policy{})
+
+	// Test GET is retried
+	calls := setupUnavailableResponder(t, orchInventory, "inventory-response.json")
+	actual, err := orchClient.Inventory()
+	require.Nil(t, err)
+	require.Equal(t, expectedInventory, actual)
+	require.Equal(t, 2, calls())
+
+	// Test read only POST is retried
+	calls = setupUnavailableResponder(t, orchInventory, "inventory-response.json")
+	actual, err = orchClient.InventoryCheck([]string{"foo"})
+	require.Nil(t, err)
+	require.Equal(t, expectedInventory, actual)
+	require.Equal(t, 2, calls())
+
+	// Test command is not retried
+	calls = setupUnavailableResponder(t, orchCommandTask, "command-task-response.json")
+	job, err := orchClient.CommandTask(&TaskRequest{Task: "package", Scope: NewNodesScope("foo")})
+	require.Nil(t, job)
+	testHTTPError(t, job, err, http.StatusServiceUnavailable)
+	require.Equal(t, 1, calls())
+}
+
+func TestSetTokenSource(t *testing.T) {
+	client := NewClient(orchHostURL, "fixed", nil)
+	client.SetTokenSource(tokenauth.StaticTokenSource("from-source"))
+
+	// Test the source is kept when the transport is replaced
+	transport := httpmock.NewMockTransport()
+	client.SetTransport(transport)
+	transport.RegisterResponder(http.MethodGet, orchHostURL+orchInventory, func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "from-source", req.Header.Get("X-Authentication"))
+		response := httpmock.NewStringResponse(http.StatusOK, `{"items": []}`)
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	_, err := client.Inventory()
+	require.Nil(t, err)
+	require.Equal(t, 1, transport.GetTotalCallCount())
+}
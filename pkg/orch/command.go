@@ -1,6 +1,7 @@
 package orch
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -16,8 +17,14 @@ const (
 
 // CommandTask runs a permitted task job across a set of nodes (POST /command/task)
 func (c *Client) CommandTask(taskRequest *TaskRequest) (*JobID, error) {
+	return c.CommandTaskCtx(context.Background(), taskRequest)
+}
+
+// CommandTaskCtx is CommandTask with a context that cancels the request when done
+func (c *Client) CommandTaskCtx(ctx context.Context, taskRequest *TaskRequest) (*JobID, error) {
 	payload := JobID{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(taskRequest).
 		Post(orchCommandTask)
@@ -46,6 +53,7 @@ type JobID struct {
 // Params	The parameters to pass to the task. This key is required, but can be an empty object.
 // Targets	A collection of target objects used for running the task on nodes through SSH or WinRM via Bolt server.
 // Userdata	An object of arbitrary key/value data supplied to the job.
+// Timeout	The maximum number of seconds allowed for the task to run on any single node.
 // REF: https://puppet.com/docs/pe/2019.8/orchestrator_api_commands_endpoint.html#orchestrator_api_post_command_task
 type TaskRequest struct {
 	Environment string                 `json:"environment,omitempty"`
@@ -54,12 +62,21 @@ type TaskRequest struct {
 	Scope       Scope                  `json:"scope"`
 	Description string                 `json:"description"`
 	Noop        bool                   `json:"noop"`
+	Targets     []Target               `json:"targets,omitempty"`
+	Userdata    map[string]interface{} `json:"userdata,omitempty"`
+	Timeout     int                    `json:"timeout,omitempty"`
 }
 
 // CommandScheduleTask schedules a task to run at a future date and time (POST /command/schedule_task)
 func (c *Client) CommandScheduleTask(scheduleTaskRequest *ScheduleTaskRequest) (*ScheduledJobID, error) {
+	return c.CommandScheduleTaskCtx(context.Background(), scheduleTaskRequest)
+}
+
+// CommandScheduleTaskCtx is CommandScheduleTask with a context that cancels the request when done
+func (c *Client) CommandScheduleTaskCtx(ctx context.Context, scheduleTaskRequest *ScheduleTaskRequest) (*ScheduledJobID, error) {
 	payload := ScheduledJobID{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(scheduleTaskRequest).
 		Post(orchCommandScheduleTask)
@@ -98,8 +115,14 @@ type ScheduleTaskRequest struct {
 
 // CommandTaskTarget creates a new task-target (POST /command/task_target)
 func (c *Client) CommandTaskTarget(taskTargetRequest *TaskTargetRequest) (*TaskTargetJobID, error) {
+	return c.CommandTaskTargetCtx(context.Background(), taskTargetRequest)
+}
+
+// CommandTaskTargetCtx is CommandTaskTarget with a context that cancels the request when done
+func (c *Client) CommandTaskTargetCtx(ctx context.Context, taskTargetRequest *TaskTargetRequest) (*TaskTargetJobID, error) {
 	payload := TaskTargetJobID{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(taskTargetRequest).
 		Post(orchCommandTaskTarget)
@@ -131,8 +154,14 @@ type TaskTargetRequest struct {
 
 // CommandPlanRun runs a plan via the plan executor (POST /command/plan_run)
 func (c *Client) CommandPlanRun(planRunRequest *PlanRunRequest) (*PlanRunJobID, error) {
+	return c.CommandPlanRunCtx(context.Background(), planRunRequest)
+}
+
+// CommandPlanRunCtx is CommandPlanRun with a context that cancels the request when done
+func (c *Client) CommandPlanRunCtx(ctx context.Context, planRunRequest *PlanRunRequest) (*PlanRunJobID, error) {
 	payload := PlanRunJobID{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(planRunRequest).
 		Post(orchCommandPlanRun)
@@ -149,18 +178,27 @@ type PlanRunJobID struct {
 	Name string `json:"name"`
 }
 
-// PlanRunRequest describes a plan_run request
+// PlanRunRequest describes a plan_run request. When Scope is set the orchestrator resolves it to a list of
+// nodes and passes them to the plan's TargetSpec parameter.
 type PlanRunRequest struct {
 	Name        string                 `json:"plan_name"`
 	Params      map[string]interface{} `json:"params"`
 	Environment string                 `json:"environment,omitempty"`
 	Description string                 `json:"description,omitempty"`
+	Scope       *Scope                 `json:"scope,omitempty"`
+	Userdata    map[string]interface{} `json:"userdata,omitempty"`
 }
 
 // CommandStop stops a orchestrator job that is currently in progress (POST /command/stop)
 func (c *Client) CommandStop(stopRequest *StopRequest) (*StopJobID, error) {
+	return c.CommandStopCtx(context.Background(), stopRequest)
+}
+
+// CommandStopCtx is CommandStop with a context that cancels the request when done
+func (c *Client) CommandStopCtx(ctx context.Context, stopRequest *StopRequest) (*StopJobID, error) {
 	payload := StopJobID{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(stopRequest).
 		Post(orchCommandStop)
@@ -188,8 +226,14 @@ type StopRequest struct {
 
 // CommandDeploy runs the orchestrator across all nodes in an environment (POST /command/deploy)
 func (c *Client) CommandDeploy(deployRequest *DeployRequest) (*JobID, error) {
+	return c.CommandDeployCtx(context.Background(), deployRequest)
+}
+
+// CommandDeployCtx is CommandDeploy with a context that cancels the request when done
+func (c *Client) CommandDeployCtx(ctx context.Context, deployRequest *DeployRequest) (*JobID, error) {
 	payload := JobID{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(deployRequest).
 		Post(orchCommandDeploy)
@@ -214,4 +258,17 @@ type DeployRequest struct {
 	Trace              bool   `json:"trace,omitempty"`
 	Evaltrace          bool   `json:"evaltrace,omitempty"`
 	Target             string `json:"target,omitempty"`
+
+	// Puppet agent run options, timeouts are in seconds
+	FileTimeout          int                    `json:"filetimeout,omitempty"`
+	HTTPConnectTimeout   int                    `json:"http_connect_timeout,omitempty"`
+	HTTPKeepaliveTimeout int                    `json:"http_keepalive_timeout,omitempty"`
+	HTTPReadTimeout      int                    `json:"http_read_timeout,omitempty"`
+	Ordering             string                 `json:"ordering,omitempty"`
+	SkipTags             string                 `json:"skip_tags,omitempty"`
+	Tags                 string                 `json:"tags,omitempty"`
+	Timeout              int                    `json:"timeout,omitempty"`
+	UseCachedCatalog     bool                   `json:"use_cached_catalog,omitempty"`
+	UseCacheOnFailure    bool                   `json:"usecacheonfailure,omitempty"`
+	Userdata             map[string]interface{} `json:"userdata,omitempty"`
 }
@@ -24,7 +24,7 @@ func setupGetResponder(t *testing.T, url, query, responseFilename string) {
 	require.Nil(t, err)
 	response := httpmock.NewBytesResponse(200, responseBody)
 	response.Header.Set("Content-Type", "application/json")
-	if query != "" {
+	if query == "" {
 		httpmock.RegisterResponder(http.MethodGet, orchHostURL+url, httpmock.ResponderFromResponse(response))
 	} else {
 		httpmock.RegisterResponderWithQuery(http.MethodGet, orchHostURL+url, query, httpmock.ResponderFromResponse(response))
@@ -57,6 +57,28 @@ func setupPostResponder(t *testing.T, url, requestFilename, responseFilename str
 	)
 }
 
+// setupUnavailableResponder responds to GET and POST requests for url with a 503 the first time
+// and the given response file after that. It returns a func counting the calls made.
+func setupUnavailableResponder(t *testing.T, url, responseFilename string) func() int {
+	httpmock.Reset()
+	responseBody, err := os.ReadFile("testdata/apidocs/" + responseFilename)
+	require.Nil(t, err)
+
+	calls := 0
+	responder := func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, `{}`), nil
+		}
+		response := httpmock.NewBytesResponse(http.StatusOK, responseBody)
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	}
+	httpmock.RegisterResponder(http.MethodGet, orchHostURL+url, responder)
+	httpmock.RegisterResponder(http.MethodPost, orchHostURL+url, responder)
+	return func() int { return calls }
+}
+
 func setupErrorResponder(t *testing.T, url string) {
 	setupResponderWithStatusCode(t, url, http.StatusBadRequest)
 }
@@ -71,6 +93,18 @@ func setupResponderWithStatusCodeAndBody(t *testing.T, url string, statusCode in
 	require.Nil(t, err)
 	httpmock.RegisterResponder(http.MethodGet, orchHostURL+url, responder)
 	httpmock.RegisterResponder(http.MethodPost, orchHostURL+url, responder)
+	httpmock.RegisterResponder(http.MethodDelete, orchHostURL+url, responder)
+}
+
+// setupBlockingResponder responds to every request for url only once the request's context is done
+func setupBlockingResponder(url string) {
+	httpmock.Reset()
+	responder := func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	httpmock.RegisterResponder(http.MethodGet, orchHostURL+url, responder)
+	httpmock.RegisterResponder(http.MethodPost, orchHostURL+url, responder)
 }
 
 func testHTTPError(t *testing.T, actual interface{}, err error, statusCode int) {
@@ -101,6 +135,13 @@ var expectedError = &OrchestratorError{
 	StatusCode: 400,
 }
 
+// expectedErrorAt returns expectedError as it is returned for a request for path
+func expectedErrorAt(path string) *OrchestratorError {
+	e := *expectedError
+	e.Path = path
+	return &e
+}
+
 var expectedJobNotFoundErr = &OrchestratorError{
 	Kind:       "puppetlabs.orchestrator/unknown-environment",
 	Msg:        "/orchestrator/v1/jobs/123 error: job not found",
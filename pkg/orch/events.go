@@ -0,0 +1,113 @@
+package orch
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Job event types reported by the orchestrator
+const (
+	EventNodeErrored  = "node_errored"
+	EventNodeFailed   = "node_failed"
+	EventNodeFinished = "node_finished"
+	EventNodeRunning  = "node_running"
+	EventNodeSkipped  = "node_skipped"
+	EventJobAborted   = "job_aborted"
+	EventJobStopping  = "job_stopping"
+	EventJobFinished  = "job_finished"
+)
+
+// JobEventFollower tails the events of a job, fetching from the last seen event until the job ends
+type JobEventFollower struct {
+	client   *Client
+	jobID    string
+	start    string
+	policy   PollPolicy
+	interval time.Duration
+	pending  []JobEvent
+	jobEnded bool
+	done     bool
+}
+
+// FollowJobEvents returns a follower for the events of the given job, starting at the event ID start
+// which may be empty to start from the first event. A zero policy uses DefaultPollPolicy.
+func (c *Client) FollowJobEvents(jobID, start string, policy PollPolicy) *JobEventFollower {
+	if policy.Interval <= 0 {
+		policy = DefaultPollPolicy()
+	}
+	return &JobEventFollower{
+		client:   c,
+		jobID:    jobID,
+		start:    start,
+		policy:   policy,
+		interval: policy.Interval,
+	}
+}
+
+// Next blocks until the next event of the job is available and returns it. Once the job has ended and
+// all of its events have been returned the error will be io.EOF.
+func (f *JobEventFollower) Next(ctx context.Context) (*JobEvent, error) {
+	for len(f.pending) == 0 {
+		if f.done {
+			return nil, io.EOF
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		events, err := f.client.JobEventsCtx(ctx, f.jobID, f.start)
+		if err != nil {
+			return nil, err
+		}
+		f.advance(events)
+		if len(f.pending) > 0 {
+			f.interval = f.policy.Interval
+			break
+		}
+
+		// The job is checked before the final fetch of events so that nothing emitted
+		// between the two calls is missed.
+		if f.jobEnded {
+			f.done = true
+			continue
+		}
+		job, err := f.client.JobCtx(ctx, f.jobID)
+		if err != nil {
+			return nil, err
+		}
+		if IsTerminalJobState(job.State) {
+			f.jobEnded = true
+			continue
+		}
+
+		if err := sleepContext(ctx, f.interval); err != nil {
+			return nil, err
+		}
+		f.interval = f.policy.next(f.interval)
+	}
+
+	event := f.pending[0]
+	f.pending = f.pending[1:]
+	return &event, nil
+}
+
+// Start returns the event ID the follower will fetch from next
+func (f *JobEventFollower) Start() string {
+	return f.start
+}
+
+// advance queues the fetched events and moves the start cursor past them
+func (f *JobEventFollower) advance(events *JobEvents) {
+	f.pending = append(f.pending, events.Items...)
+	if events.NextEvents.Event != "" {
+		f.start = events.NextEvents.Event
+		return
+	}
+	if len(events.Items) > 0 {
+		if id, err := strconv.Atoi(events.Items[len(events.Items)-1].ID); err == nil {
+			f.start = strconv.Itoa(id + 1)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package orch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowJobEvents(t *testing.T) {
+	setupJobSequenceResponder(t, "352", []Job{
+		{Name: "352", State: JobStateRunning},
+		{Name: "352", State: JobStateFinished},
+	})
+
+	// Events are returned a page at a time and the start cursor is checked on each request
+	pages := map[string]JobEvents{
+		"": {
+			Items:      []JobEvent{{ID: "1", Type: EventNodeRunning}},
+			NextEvents: NextEvents{Event: "2"},
+		},
+		"2": {
+			Items: []JobEvent{{ID: "2", Type: EventNodeFinished}, {ID: "3", Type: EventJobFinished}},
+		},
+	}
+	httpmock.RegisterResponder(http.MethodGet, orchHostURL+strings.ReplaceAll(orchJobEvents, "{job-id}", "352"),
+		func(req *http.Request) (*http.Response, error) {
+			page, ok := pages[req.URL.Query().Get("start")]
+			if !ok {
+				page = JobEvents{NextEvents: NextEvents{Event: req.URL.Query().Get("start")}}
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, page)
+		},
+	)
+
+	follower := orchClient.FollowJobEvents("352", "", testPollPolicy)
+	var ids []string
+	for {
+		event, err := follower.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		ids = append(ids, event.ID)
+	}
+	require.Equal(t, []string{"1", "2", "3"}, ids)
+	require.Equal(t, "4", follower.Start())
+
+	_, err := follower.Next(context.Background())
+	require.Equal(t, io.EOF, err)
+}
+
+func TestFollowJobEventsCancelled(t *testing.T) {
+	setupJobSequenceResponder(t, "352", []Job{{Name: "352", State: JobStateRunning}})
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, JobEvents{})
+	require.Nil(t, err)
+	httpmock.RegisterResponder(http.MethodGet, orchHostURL+strings.ReplaceAll(orchJobEvents, "{job-id}", "352"), responder)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	event, err := orchClient.FollowJobEvents("352", "", testPollPolicy).Next(ctx)
+	require.Nil(t, event)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFollowJobEventsError(t *testing.T) {
+	setupErrorResponder(t, strings.ReplaceAll(orchJobEvents, "{job-id}", "352"))
+	event, err := orchClient.FollowJobEvents("352", "", testPollPolicy).Next(context.Background())
+	require.Nil(t, event)
+	require.Equal(t, expectedErrorAt(strings.ReplaceAll(orchJobEvents, "{job-id}", "352")), err)
+}
@@ -1,8 +1,11 @@
 package orch
 
 import (
+	"context"
 	"fmt"
 	"strings"
+
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
 )
 
 const (
@@ -12,8 +15,13 @@ const (
 
 // Inventory lists all nodes that are connected to the PCP broker (GET /inventory)
 func (c *Client) Inventory() ([]InventoryNode, error) {
+	return c.InventoryCtx(context.Background())
+}
+
+// InventoryCtx is Inventory with a context that cancels the request when done
+func (c *Client) InventoryCtx(ctx context.Context) ([]InventoryNode, error) {
 	payload := map[string][]InventoryNode{}
-	r, err := c.resty.R().SetResult(&payload).Get(orchInventory)
+	r, err := c.resty.R().SetContext(ctx).SetResult(&payload).Get(orchInventory)
 
 	if err = ProcessError(r, err, fmt.Sprintf("%s error: %s", orchInventory, r.Status())); err != nil {
 		return nil, err
@@ -25,8 +33,14 @@ func (c *Client) Inventory() ([]InventoryNode, error) {
 
 // InventoryNode returns information about whether the requested node is connected to the PCP broker (GET /inventory/:node)
 func (c *Client) InventoryNode(node string) (*InventoryNode, error) {
+	return c.InventoryNodeCtx(context.Background(), node)
+}
+
+// InventoryNodeCtx is InventoryNode with a context that cancels the request when done
+func (c *Client) InventoryNodeCtx(ctx context.Context, node string) (*InventoryNode, error) {
 	payload := &InventoryNode{}
 	req := c.resty.R().
+		SetContext(ctx).
 		SetResult(payload).
 		SetPathParams(map[string]string{
 			"node": node,
@@ -43,10 +57,17 @@ func (c *Client) InventoryNode(node string) (*InventoryNode, error) {
 
 // InventoryCheck checks if the given list of nodes is connected to the PCP broker (POST /inventory)
 func (c *Client) InventoryCheck(nodes []string) ([]InventoryNode, error) {
+	return c.InventoryCheckCtx(context.Background(), nodes)
+}
+
+// InventoryCheckCtx is InventoryCheck with a context that cancels the request when done
+func (c *Client) InventoryCheckCtx(ctx context.Context, nodes []string) ([]InventoryNode, error) {
 	payload := map[string][]InventoryNode{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(map[string]interface{}{"nodes": nodes}).
+		AddRetryCondition(retry.Transient).
 		Post(orchInventory)
 
 	if err = ProcessError(r, err, fmt.Sprintf("%s error: %s", orchInventory, r.Status())); err != nil {
@@ -1,10 +1,12 @@
 package orch
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,12 +21,23 @@ func TestInventory(t *testing.T) {
 	setupErrorResponder(t, orchInventory)
 	actual, err = orchClient.Inventory()
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchInventory), err)
+	var apiErr *apierror.Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, &apierror.Error{
+		Service:    apierror.ServiceOrchestrator,
+		StatusCode: http.StatusBadRequest,
+		Kind:       expectedError.Kind,
+		Msg:        expectedError.Msg,
+		Path:       orchInventory,
+	}, apiErr)
+	require.ErrorIs(t, err, apierror.ErrBadRequest)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchInventory, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
 	actual, err = orchClient.Inventory()
 	testHTTPError(t, actual, err, http.StatusNotFound)
+	require.True(t, apierror.IsNotFound(err))
 }
 
 func TestInventoryNode(t *testing.T) {
@@ -40,7 +53,7 @@ func TestInventoryNode(t *testing.T) {
 	setupErrorResponder(t, orchInventoryNodeFoo)
 	actual, err = orchClient.InventoryNode("foo")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchInventoryNodeFoo), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchInventoryNodeFoo, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
@@ -59,7 +72,7 @@ func TestInventoryCheck(t *testing.T) {
 	setupErrorResponder(t, orchInventory)
 	actual, err = orchClient.InventoryCheck([]string{"foo.example.com", "bar.example.com", "baz.example.com"})
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchInventory), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchInventory, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
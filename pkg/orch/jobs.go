@@ -1,37 +1,139 @@
 package orch
 
 import (
-	"fmt"
+	"context"
+	"io"
 	"strings"
+	"time"
 )
 
 const (
 	orchJob       = "/orchestrator/v1/jobs/{job-id}"
 	orchJobNodes  = "/orchestrator/v1/jobs/{job-id}/nodes"
 	orchJobReport = "/orchestrator/v1/jobs/{job-id}/report"
+	orchJobEvents = "/orchestrator/v1/jobs/{job-id}/events"
 	orchJobs      = "/orchestrator/v1/jobs"
 )
 
 // Jobs lists all of the jobs known to the orchestrator (GET /jobs)
 func (c *Client) Jobs() (*Jobs, error) {
+	return c.JobsCtx(context.Background())
+}
+
+// JobsCtx is Jobs with a context that cancels the request when done
+func (c *Client) JobsCtx(ctx context.Context) (*Jobs, error) {
+	return c.JobsWithOptionsCtx(ctx, nil)
+}
+
+// JobsWithOptions lists the jobs known to the orchestrator, filtered and paginated by options which may be nil (GET /jobs)
+func (c *Client) JobsWithOptions(options *JobsOptions) (*Jobs, error) {
+	return c.JobsWithOptionsCtx(context.Background(), options)
+}
+
+// JobsWithOptionsCtx is JobsWithOptions with a context that cancels the request when done
+func (c *Client) JobsWithOptionsCtx(ctx context.Context, options *JobsOptions) (*Jobs, error) {
 	payload := &Jobs{}
-	r, err := c.resty.R().SetResult(&payload).Get(orchJobs)
+	req := c.resty.R().SetContext(ctx).SetResult(&payload)
+	if options != nil {
+		req.SetQueryParams(options.toParams())
+	}
+	r, err := req.Get(orchJobs)
+
+	if err = ProcessError(r, err, orchJobs); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// JobsOptions filters and paginates the jobs returned by JobsWithOptions and PaginatedJobs.
+// OrderBy is one of owner, timestamp, environment, name or state, Order is asc or desc and
+// Type is one of deploy, task or plan_task. Zero values are not sent.
+type JobsOptions struct {
+	Limit              int
+	Offset             int
+	OrderBy            string
+	Order              string
+	Type               string
+	Owner              string
+	MinFinishTimestamp time.Time
+	MaxFinishTimestamp time.Time
+}
+
+// toParams will take the JobsOptions struct and convert into a form Client SetQueryParam accepts
+func (o JobsOptions) toParams() map[string]string {
+	params := Pagination{Limit: o.Limit, Offset: o.Offset, OrderBy: o.OrderBy, Order: o.Order, Type: o.Type}.toParams()
+	if o.Owner != "" {
+		params["owner"] = o.Owner
+	}
+	if !o.MinFinishTimestamp.IsZero() {
+		params["min_finish_timestamp"] = o.MinFinishTimestamp.UTC().Format(time.RFC3339)
+	}
+	if !o.MaxFinishTimestamp.IsZero() {
+		params["max_finish_timestamp"] = o.MaxFinishTimestamp.UTC().Format(time.RFC3339)
+	}
+	return params
+}
+
+// PaginatedJobs works just like JobsWithOptions, but returns a JobsCursor that steps through every page
+// of matching jobs starting at options.Offset. If options.Limit is 0 the orchestrator's default page size is used.
+func (c *Client) PaginatedJobs(options *JobsOptions) *JobsCursor {
+	cursor := &JobsCursor{client: c}
+	if options != nil {
+		cursor.options = *options
+	}
+	return cursor
+}
+
+// JobsCursor is a pagination cursor that provides convenience methods for stepping through pages of jobs
+type JobsCursor struct {
+	client  *Client
+	options JobsOptions
+	total   int
+	fetched bool
+	done    bool
+}
+
+// Next returns the next page of jobs. Once every page has been returned it returns nil and io.EOF.
+func (jc *JobsCursor) Next() ([]Job, error) {
+	return jc.NextCtx(context.Background())
+}
+
+// NextCtx is Next with a context that cancels the request for the page when done
+func (jc *JobsCursor) NextCtx(ctx context.Context) ([]Job, error) {
+	if jc.done || (jc.fetched && jc.options.Offset >= jc.total) {
+		jc.done = true
+		return nil, io.EOF
+	}
+
+	jobs, err := jc.client.JobsWithOptionsCtx(ctx, &jc.options)
 	if err != nil {
 		return nil, err
 	}
-	if r.IsError() {
-		if r.Error() != nil {
-			return nil, r.Error().(error)
-		}
-		return nil, fmt.Errorf("%s error: %s", orchJobs, r.Status())
+	jc.fetched = true
+	jc.total = jobs.Pagination.Total
+	jc.options.Offset += len(jobs.Items)
+	if len(jobs.Items) == 0 {
+		jc.done = true
+		return nil, io.EOF
 	}
-	return payload, nil
+	return jobs.Items, nil
+}
+
+// Total returns the total number of jobs matching the options, as reported with the last page fetched
+func (jc *JobsCursor) Total() int {
+	return jc.total
 }
 
 // Job lists all details of a given job (GET /jobs/:job-id)
 func (c *Client) Job(jobID string) (*Job, error) {
+	return c.JobCtx(context.Background(), jobID)
+}
+
+// JobCtx is Job with a context that cancels the request when done
+func (c *Client) JobCtx(ctx context.Context, jobID string) (*Job, error) {
 	payload := &Job{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetPathParams(map[string]string{"job-id": jobID}).
 		Get(orchJob)
@@ -45,8 +147,14 @@ func (c *Client) Job(jobID string) (*Job, error) {
 
 // JobReport returns the report for a given job (GET /jobs/:job-id/report)
 func (c *Client) JobReport(jobID string) (*JobReport, error) {
+	return c.JobReportCtx(context.Background(), jobID)
+}
+
+// JobReportCtx is JobReport with a context that cancels the request when done
+func (c *Client) JobReportCtx(ctx context.Context, jobID string) (*JobReport, error) {
 	payload := &JobReport{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetPathParams(map[string]string{"job-id": jobID}).
 		Get(orchJobReport)
@@ -59,8 +167,14 @@ func (c *Client) JobReport(jobID string) (*JobReport, error) {
 
 // JobNodes lists all of the nodes associated with a given job (GET /jobs/:job-id/nodes)
 func (c *Client) JobNodes(jobID string) (*JobNodes, error) {
+	return c.JobNodesCtx(context.Background(), jobID)
+}
+
+// JobNodesCtx is JobNodes with a context that cancels the request when done
+func (c *Client) JobNodesCtx(ctx context.Context, jobID string) (*JobNodes, error) {
 	payload := &JobNodes{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetPathParams(map[string]string{"job-id": jobID}).
 		Get(orchJobNodes)
@@ -71,6 +185,30 @@ func (c *Client) JobNodes(jobID string) (*JobNodes, error) {
 	return payload, nil
 }
 
+// JobEvents returns the events for a given job starting from the event ID start, which may be empty
+// to return all events (GET /jobs/:job-id/events)
+func (c *Client) JobEvents(jobID, start string) (*JobEvents, error) {
+	return c.JobEventsCtx(context.Background(), jobID, start)
+}
+
+// JobEventsCtx is JobEvents with a context that cancels the request when done
+func (c *Client) JobEventsCtx(ctx context.Context, jobID, start string) (*JobEvents, error) {
+	payload := &JobEvents{}
+	req := c.resty.R().
+		SetContext(ctx).
+		SetResult(&payload).
+		SetPathParams(map[string]string{"job-id": jobID})
+	if start != "" {
+		req.SetQueryParam("start", start)
+	}
+	r, err := req.Get(orchJobEvents)
+
+	if err = ProcessError(r, err, strings.ReplaceAll(orchJobEvents, "{job-id}", jobID)); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
 // Jobs contains data about all jobs
 type Jobs struct {
 	Items      []Job      `json:"items"`
@@ -156,6 +294,12 @@ type JobEvent struct {
 	Message string `json:"message"`
 }
 
+// JobEvents is a list of events for a given job
+type JobEvents struct {
+	Items      []JobEvent `json:"items"`
+	NextEvents NextEvents `json:"next-events"`
+}
+
 // JobNodes is a list of all nodes associated with a given job
 type JobNodes struct {
 	Items      []JobNode  `json:"items"`
@@ -1,13 +1,18 @@
 package orch
 
 import (
+	"context"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/fatih/structs"
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,7 +27,102 @@ func TestJobs(t *testing.T) {
 	setupErrorResponder(t, orchJobs)
 	actual, err = orchClient.Jobs()
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchJobs), err)
+}
+
+func TestJobsWithOptions(t *testing.T) {
+	options := &JobsOptions{
+		Limit:              10,
+		Offset:             10,
+		OrderBy:            "timestamp",
+		Order:              "asc",
+		Type:               "task",
+		Owner:              "admin",
+		MinFinishTimestamp: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC),
+		MaxFinishTimestamp: time.Date(2020, 5, 1, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+	}
+
+	// Test success
+	setupGetResponder(t, orchJobs, "limit=10&offset=10&order_by=timestamp&order=asc&type=task&owner=admin"+
+		"&min_finish_timestamp=2020-04-01T00:00:00Z&max_finish_timestamp=2020-05-01T00:00:00Z", "jobs-response.json")
+	actual, err := orchClient.JobsWithOptions(options)
+	require.Nil(t, err)
+	require.Len(t, actual.Items, 2)
+
+	// Test error
+	setupErrorResponder(t, orchJobs)
+	actual, err = orchClient.JobsWithOptions(options)
+	require.Nil(t, actual)
+	require.Equal(t, expectedErrorAt(orchJobs), err)
+
+	// Test HTTP error
+	setupResponderWithStatusCodeAndBody(t, orchJobs, http.StatusBadRequest, []byte(`{"StatusCode": 400}`))
+	actual, err = orchClient.JobsWithOptions(options)
+	testHTTPError(t, actual, err, http.StatusBadRequest)
+}
+
+// setupPaginatedJobsResponder serves total jobs named by their index, a page of limit at a time
+func setupPaginatedJobsResponder(total int, failAtOffset int) {
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodGet, orchHostURL+orchJobs,
+		func(req *http.Request) (*http.Response, error) {
+			offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+			if offset == failAtOffset {
+				return httpmock.NewJsonResponse(http.StatusBadRequest, expectedError)
+			}
+			page := Jobs{Pagination: Pagination{Limit: limit, Offset: offset, Total: total}}
+			for i := offset; i < offset+limit && i < total; i++ {
+				page.Items = append(page.Items, Job{Name: strconv.Itoa(i)})
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, page)
+		},
+	)
+}
+
+func TestPaginatedJobs(t *testing.T) {
+	setupPaginatedJobsResponder(25, -1)
+
+	cursor := orchClient.PaginatedJobs(&JobsOptions{Limit: 10, Type: "task"})
+	var names []string
+	pages := 0
+	for {
+		jobs, err := cursor.Next()
+		if err == io.EOF {
+			require.Nil(t, jobs)
+			break
+		}
+		require.Nil(t, err)
+		pages++
+		for _, job := range jobs {
+			names = append(names, job.Name)
+		}
+	}
+	require.Equal(t, 3, pages)
+	require.Len(t, names, 25)
+	require.Equal(t, "24", names[24])
+	require.Equal(t, 25, cursor.Total())
+
+	_, err := cursor.Next()
+	require.Equal(t, io.EOF, err)
+
+	// Test no results
+	setupPaginatedJobsResponder(0, -1)
+	_, err = orchClient.PaginatedJobs(nil).Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestPaginatedJobsWithError(t *testing.T) {
+	setupPaginatedJobsResponder(25, 10)
+
+	cursor := orchClient.PaginatedJobs(&JobsOptions{Limit: 10})
+	jobs, err := cursor.Next()
+	require.Nil(t, err)
+	require.Len(t, jobs, 10)
+
+	jobs, err = cursor.Next()
+	require.Nil(t, jobs)
+	require.Equal(t, expectedErrorAt(orchJobs), err)
 }
 
 func TestJob(t *testing.T) {
@@ -38,7 +138,7 @@ func TestJob(t *testing.T) {
 	setupErrorResponder(t, testURL)
 	actual, err = orchClient.Job("123")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(testURL), err)
 	require.False(t, errors.Is(err, expectedJobNotFoundErr))
 
 	// Test HTTP error
@@ -47,6 +147,31 @@ func TestJob(t *testing.T) {
 	testHTTPError(t, actual, err, http.StatusNotFound)
 }
 
+func TestJobCtx(t *testing.T) {
+	testURL := strings.ReplaceAll(orchJob, "{job-id}", "123")
+
+	// Test success
+	setupGetResponder(t, testURL, "", "job-response.json")
+	actual, err := orchClient.JobCtx(context.Background(), "123")
+	require.Nil(t, err)
+	require.Equal(t, "1", actual.Name)
+
+	// Test cancellation reaches the request
+	setupBlockingResponder(testURL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actual, err = orchClient.JobCtx(ctx, "123")
+	require.Nil(t, actual)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// Test deadline
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	actual, err = orchClient.JobCtx(ctx, "123")
+	require.Nil(t, actual)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestJobReport(t *testing.T) {
 	testURL := strings.ReplaceAll(orchJobReport, "{job-id}", "123")
 
@@ -60,7 +185,7 @@ func TestJobReport(t *testing.T) {
 	setupErrorResponder(t, testURL)
 	actual, err = orchClient.JobReport("123")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(testURL), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, testURL, http.StatusForbidden, []byte(`{"StatusCode": 400}`))
@@ -81,10 +206,39 @@ func TestJobNodes(t *testing.T) {
 	setupErrorResponder(t, testURL)
 	actual, err = orchClient.JobNodes("123")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(testURL), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, testURL, http.StatusBadRequest, []byte(`{"StatusCode": 400}`))
 	actual, err = orchClient.JobNodes("123")
 	testHTTPError(t, actual, err, http.StatusBadRequest)
 }
+
+func TestJobEvents(t *testing.T) {
+	testURL := strings.ReplaceAll(orchJobEvents, "{job-id}", "352")
+
+	// Test success
+	setupGetResponder(t, testURL, "", "job-events-response.json")
+	actual, err := orchClient.JobEvents("352", "")
+	require.Nil(t, err)
+	require.False(t, structs.HasZero(actual), spew.Sdump(actual))
+	require.Len(t, actual.Items, 2)
+	require.Equal(t, "1272", actual.NextEvents.Event)
+
+	// Test with start
+	setupGetResponder(t, testURL, "start=1270", "job-events-response.json")
+	actual, err = orchClient.JobEvents("352", "1270")
+	require.Nil(t, err)
+	require.Equal(t, "puppet-agent.example.com", actual.Items[0].Details.Node)
+
+	// Test error
+	setupErrorResponder(t, testURL)
+	actual, err = orchClient.JobEvents("352", "")
+	require.Nil(t, actual)
+	require.Equal(t, expectedErrorAt(testURL), err)
+
+	// Test HTTP error
+	setupResponderWithStatusCodeAndBody(t, testURL, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
+	actual, err = orchClient.JobEvents("352", "")
+	testHTTPError(t, actual, err, http.StatusNotFound)
+}
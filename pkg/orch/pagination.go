@@ -0,0 +1,24 @@
+package orch
+
+import "strconv"
+
+// toParams will take the Pagination struct and convert into a form Client SetQueryParam accepts
+func (p Pagination) toParams() map[string]string {
+	params := map[string]string{}
+	if p.Limit > 0 {
+		params["limit"] = strconv.Itoa(p.Limit)
+	}
+	if p.Offset > 0 {
+		params["offset"] = strconv.Itoa(p.Offset)
+	}
+	if p.OrderBy != "" {
+		params["order_by"] = p.OrderBy
+	}
+	if p.Order != "" {
+		params["order"] = p.Order
+	}
+	if p.Type != "" {
+		params["type"] = p.Type
+	}
+	return params
+}
@@ -0,0 +1,103 @@
+package orch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParamErrorKind identifies the problem found with a task parameter
+type ParamErrorKind string
+
+// Kinds of parameter error reported by ValidateParams
+const (
+	ParamMissing      ParamErrorKind = "missing"
+	ParamUnknown      ParamErrorKind = "unknown"
+	ParamTypeMismatch ParamErrorKind = "type_mismatch"
+)
+
+// ParamError describes a single problem with a task parameter.
+// Expected is the Puppet type from the task metadata and Actual the Puppet type of the supplied value,
+// each is only set when relevant to the kind of error.
+type ParamError struct {
+	Kind     ParamErrorKind
+	Param    string
+	Expected string
+	Actual   string
+}
+
+func (e ParamError) Error() string {
+	switch e.Kind {
+	case ParamMissing:
+		return fmt.Sprintf("parameter %s is required (%s)", e.Param, e.Expected)
+	case ParamUnknown:
+		return fmt.Sprintf("parameter %s is not accepted by the task", e.Param)
+	}
+	return fmt.Sprintf("parameter %s expects %s, got %s", e.Param, e.Expected, e.Actual)
+}
+
+// ParamErrors lists every problem found by ValidateParams
+type ParamErrors []ParamError
+
+func (e ParamErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, pe := range e {
+		msgs[i] = pe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateParams checks the given parameters, e.g. TaskRequest.Params, against the parameter types in the
+// task metadata. It returns ParamErrors listing missing required parameters, parameters the task does not
+// accept and values that do not match the declared type, or nil if there are none.
+// Parameters without a type, or with a type that cannot be parsed, accept any value. Metaparameters
+// starting with an underscore, such as _noop, and tasks without parameter metadata accept any parameters.
+func (m TaskMetadata) ValidateParams(params map[string]interface{}) error {
+	errs := ParamErrors{}
+
+	for _, name := range sortedKeys(m.Parameters) {
+		expected := m.Parameters[name].Type
+		t, err := ParsePuppetType(expected)
+		if expected == "" || err != nil {
+			t = &PuppetType{expr: "Any", m: anyMatcher{}}
+		}
+
+		value, ok := params[name]
+		if !ok {
+			if !t.Optional() {
+				errs = append(errs, ParamError{Kind: ParamMissing, Param: name, Expected: t.String()})
+			}
+			continue
+		}
+
+		normalized, err := normalizeValue(value)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", name, err)
+		}
+		if !t.m.match(normalized) {
+			errs = append(errs, ParamError{Kind: ParamTypeMismatch, Param: name, Expected: t.String(), Actual: typeName(normalized)})
+		}
+	}
+
+	if m.Parameters != nil {
+		for _, name := range sortedKeys(params) {
+			if _, ok := m.Parameters[name]; !ok && !strings.HasPrefix(name, "_") {
+				errs = append(errs, ParamError{Kind: ParamUnknown, Param: name})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
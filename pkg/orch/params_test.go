@@ -0,0 +1,72 @@
+package orch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testTaskMetadata = TaskMetadata{
+	Parameters: map[string]TaskParam{
+		"action":  {Type: "Enum[start, stop]"},
+		"name":    {Type: "String[1]"},
+		"timeout": {Type: "Optional[Integer[1]]"},
+		"extra":   {},
+		"path":    {Type: "Stdlib::Absolutepath"},
+	},
+}
+
+func TestValidateParams(t *testing.T) {
+	// Test valid params
+	err := testTaskMetadata.ValidateParams(map[string]interface{}{
+		"action":  "start",
+		"name":    "httpd",
+		"timeout": 30,
+		"path":    "/var/log/httpd",
+		"_noop":   true,
+	})
+	require.Nil(t, err)
+
+	// Test every kind of error
+	err = testTaskMetadata.ValidateParams(map[string]interface{}{
+		"action":  "restart",
+		"timeout": "30",
+		"other":   1,
+	})
+	var paramErrs ParamErrors
+	require.True(t, errors.As(err, &paramErrs))
+	require.Equal(t, ParamErrors{
+		{Kind: ParamTypeMismatch, Param: "action", Expected: "Enum[start, stop]", Actual: "String"},
+		{Kind: ParamMissing, Param: "name", Expected: "String[1]"},
+		{Kind: ParamMissing, Param: "path", Expected: "Stdlib::Absolutepath"},
+		{Kind: ParamTypeMismatch, Param: "timeout", Expected: "Optional[Integer[1]]", Actual: "String"},
+		{Kind: ParamUnknown, Param: "other"},
+	}, paramErrs)
+	require.Equal(t, "parameter action expects Enum[start, stop], got String; "+
+		"parameter name is required (String[1]); "+
+		"parameter path is required (Stdlib::Absolutepath); "+
+		"parameter timeout expects Optional[Integer[1]], got String; "+
+		"parameter other is not accepted by the task", err.Error())
+}
+
+func TestValidateParamsAlias(t *testing.T) {
+	// Test a parameter with a type alias is required unless it is Optional
+	metadata := TaskMetadata{Parameters: map[string]TaskParam{
+		"path":   {Type: "Stdlib::Absolutepath"},
+		"backup": {Type: "Optional[Stdlib::Absolutepath]"},
+	}}
+	err := metadata.ValidateParams(map[string]interface{}{})
+	require.Equal(t, ParamErrors{{Kind: ParamMissing, Param: "path", Expected: "Stdlib::Absolutepath"}}, err)
+
+	err = metadata.ValidateParams(map[string]interface{}{"path": "/tmp"})
+	require.Nil(t, err)
+}
+
+func TestValidateParamsWithoutMetadata(t *testing.T) {
+	err := TaskMetadata{}.ValidateParams(map[string]interface{}{"anything": 1})
+	require.Nil(t, err)
+
+	err = TaskMetadata{Parameters: map[string]TaskParam{}}.ValidateParams(map[string]interface{}{"anything": 1})
+	require.Equal(t, ParamErrors{{Kind: ParamUnknown, Param: "anything"}}, err)
+}
@@ -0,0 +1,197 @@
+package orch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	orchPlanJobs      = "/orchestrator/v1/plan_jobs"
+	orchPlanJob       = "/orchestrator/v1/plan_jobs/{job-id}"
+	orchPlanJobEvents = "/orchestrator/v1/plan_jobs/{job-id}/events"
+)
+
+// Plan job states reported by the orchestrator
+const (
+	PlanJobStatePending  = "pending"
+	PlanJobStateRunning  = "running"
+	PlanJobStateSuccess  = "success"
+	PlanJobStateFailure  = "failure"
+	PlanJobStateStopping = "stopping"
+	PlanJobStateStopped  = "stopped"
+)
+
+// Plan job event types reported by the orchestrator
+const (
+	PlanEventTaskStart    = "task_start"
+	PlanEventScriptStart  = "script_start"
+	PlanEventCommandStart = "command_start"
+	PlanEventUploadStart  = "upload_start"
+	PlanEventWaitStart    = "wait_start"
+	PlanEventApplyStart   = "apply_start"
+	PlanEventApplyPrep    = "apply_prep_start"
+	PlanEventOutMessage   = "out_message"
+	PlanEventPlanFinished = "plan_finished"
+)
+
+// IsTerminalPlanJobState returns true if the plan job state is one the orchestrator will not move on from
+func IsTerminalPlanJobState(state string) bool {
+	switch state {
+	case PlanJobStateSuccess, PlanJobStateFailure, PlanJobStateStopped:
+		return true
+	}
+	return false
+}
+
+// PlanJobs lists the plan jobs known to the orchestrator, pagination may be nil (GET /plan_jobs)
+func (c *Client) PlanJobs(pagination *Pagination) (*PlanJobs, error) {
+	return c.PlanJobsCtx(context.Background(), pagination)
+}
+
+// PlanJobsCtx is PlanJobs with a context that cancels the request when done
+func (c *Client) PlanJobsCtx(ctx context.Context, pagination *Pagination) (*PlanJobs, error) {
+	payload := &PlanJobs{}
+	req := c.resty.R().SetContext(ctx).SetResult(&payload)
+	if pagination != nil {
+		req.SetQueryParams(pagination.toParams())
+	}
+	r, err := req.Get(orchPlanJobs)
+
+	if err = ProcessError(r, err, orchPlanJobs); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// PlanJob returns the details of a given plan job, including its result once finished (GET /plan_jobs/:job-id)
+func (c *Client) PlanJob(jobID string) (*PlanJob, error) {
+	return c.PlanJobCtx(context.Background(), jobID)
+}
+
+// PlanJobCtx is PlanJob with a context that cancels the request when done
+func (c *Client) PlanJobCtx(ctx context.Context, jobID string) (*PlanJob, error) {
+	payload := &PlanJob{}
+	r, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&payload).
+		SetPathParams(map[string]string{"job-id": jobID}).
+		Get(orchPlanJob)
+
+	if err = ProcessError(r, err, strings.ReplaceAll(orchPlanJob, "{job-id}", jobID)); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// PlanJobEvents returns the events for a given plan job starting from the event ID start, which may be
+// empty to return all events (GET /plan_jobs/:job-id/events)
+func (c *Client) PlanJobEvents(jobID, start string) (*PlanJobEvents, error) {
+	return c.PlanJobEventsCtx(context.Background(), jobID, start)
+}
+
+// PlanJobEventsCtx is PlanJobEvents with a context that cancels the request when done
+func (c *Client) PlanJobEventsCtx(ctx context.Context, jobID, start string) (*PlanJobEvents, error) {
+	payload := &PlanJobEvents{}
+	req := c.resty.R().
+		SetContext(ctx).
+		SetResult(&payload).
+		SetPathParams(map[string]string{"job-id": jobID})
+	if start != "" {
+		req.SetQueryParam("start", start)
+	}
+	r, err := req.Get(orchPlanJobEvents)
+
+	if err = ProcessError(r, err, strings.ReplaceAll(orchPlanJobEvents, "{job-id}", jobID)); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// PlanJobFailedError is returned by WaitForPlanJob when a plan job ends in any state other than success
+type PlanJobFailedError struct {
+	PlanJob *PlanJob
+}
+
+func (e *PlanJobFailedError) Error() string {
+	return fmt.Sprintf("plan job %s (%s) %s", e.PlanJob.Name, e.PlanJob.Options.PlanName, e.PlanJob.State)
+}
+
+// WaitForPlanJob polls the given plan job (by name) until it reaches a terminal state and returns it.
+// If the plan fails or is stopped the plan job is returned along with a *PlanJobFailedError. If ctx is
+// done before the plan ends ctx.Err() is returned.
+func (c *Client) WaitForPlanJob(ctx context.Context, jobID string, policy PollPolicy) (*PlanJob, error) {
+	if policy.Interval <= 0 {
+		policy = DefaultPollPolicy()
+	}
+
+	interval := policy.Interval
+	for {
+		planJob, err := c.PlanJobCtx(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if IsTerminalPlanJobState(planJob.State) {
+			if planJob.State != PlanJobStateSuccess {
+				return planJob, &PlanJobFailedError{PlanJob: planJob}
+			}
+			return planJob, nil
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval = policy.next(interval)
+	}
+}
+
+// PlanJobs contains data about all plan jobs
+type PlanJobs struct {
+	Items      []PlanJob  `json:"items"`
+	Pagination Pagination `json:"pagination"`
+}
+
+// PlanJob contains data about a single plan job
+type PlanJob struct {
+	ID                string                   `json:"id"`
+	Name              string                   `json:"name"`
+	State             string                   `json:"state"`
+	Options           PlanJobOptions           `json:"options"`
+	Result            interface{}              `json:"result"`
+	Owner             map[string]interface{}   `json:"owner"`
+	Timestamp         string                   `json:"timestamp"`
+	CreatedTimestamp  string                   `json:"created_timestamp"`
+	FinishedTimestamp string                   `json:"finished_timestamp"`
+	Duration          float64                  `json:"duration"`
+	Status            map[string]PlanJobStatus `json:"status"`
+	Events            Events                   `json:"events"`
+}
+
+// PlanJobOptions are the options the plan job was started with
+type PlanJobOptions struct {
+	Description string                 `json:"description"`
+	PlanName    string                 `json:"plan_name"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	Environment string                 `json:"environment"`
+}
+
+// PlanJobStatus records when the plan job entered and left a given state
+type PlanJobStatus struct {
+	EnterTime string `json:"enter_time"`
+	ExitTime  string `json:"exit_time"`
+}
+
+// PlanJobEvents is a list of events for a given plan job
+type PlanJobEvents struct {
+	Items      []PlanJobEvent `json:"items"`
+	NextEvents NextEvents     `json:"next-events"`
+}
+
+// PlanJobEvent contains a single event from a plan job. The contents of Details depend on the event type,
+// e.g. task_start events carry the job-id of the orchestrator job running the task.
+type PlanJobEvent struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	Timestamp string                 `json:"timestamp"`
+	Details   map[string]interface{} `json:"details"`
+}
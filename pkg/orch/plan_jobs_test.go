@@ -0,0 +1,137 @@
+package orch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/fatih/structs"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanJobs(t *testing.T) {
+	// Test success
+	setupGetResponder(t, orchPlanJobs, "", "plan-jobs-response.json")
+	actual, err := orchClient.PlanJobs(nil)
+	require.Nil(t, err)
+	require.Len(t, actual.Items, 1)
+	require.Equal(t, PlanJobStateSuccess, actual.Items[0].State)
+	require.Equal(t, 1, actual.Pagination.Total)
+
+	// Test with pagination, only a request with exactly these parameters gets a response
+	setupGetResponder(t, orchPlanJobs, "limit=5&offset=10", "plan-jobs-response.json")
+	actual, err = orchClient.PlanJobs(&Pagination{Limit: 5, Offset: 10})
+	require.Nil(t, err)
+	require.Len(t, actual.Items, 1)
+
+	// Test error
+	setupErrorResponder(t, orchPlanJobs)
+	actual, err = orchClient.PlanJobs(nil)
+	require.Nil(t, actual)
+	require.Equal(t, expectedErrorAt(orchPlanJobs), err)
+
+	// Test HTTP error
+	setupResponderWithStatusCodeAndBody(t, orchPlanJobs, http.StatusForbidden, []byte(`{"StatusCode": 400}`))
+	actual, err = orchClient.PlanJobs(nil)
+	testHTTPError(t, actual, err, http.StatusForbidden)
+}
+
+func TestPlanJob(t *testing.T) {
+	testURL := strings.ReplaceAll(orchPlanJob, "{job-id}", "1234")
+
+	// Test success
+	setupGetResponder(t, testURL, "", "plan-job-response.json")
+	actual, err := orchClient.PlanJob("1234")
+	require.Nil(t, err)
+	require.False(t, structs.HasZero(actual), spew.Sdump(actual))
+	require.Equal(t, map[string]interface{}{"output": "test result"}, actual.Result)
+	require.Equal(t, "myplan", actual.Options.PlanName)
+
+	// Test error
+	setupErrorResponder(t, testURL)
+	actual, err = orchClient.PlanJob("1234")
+	require.Nil(t, actual)
+	require.Equal(t, expectedErrorAt(testURL), err)
+
+	// Test HTTP error
+	setupResponderWithStatusCodeAndBody(t, testURL, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
+	actual, err = orchClient.PlanJob("1234")
+	testHTTPError(t, actual, err, http.StatusNotFound)
+}
+
+func TestPlanJobEvents(t *testing.T) {
+	testURL := strings.ReplaceAll(orchPlanJobEvents, "{job-id}", "1234")
+
+	// Test success
+	setupGetResponder(t, testURL, "", "plan-job-events-response.json")
+	actual, err := orchClient.PlanJobEvents("1234", "")
+	require.Nil(t, err)
+	require.Len(t, actual.Items, 3)
+	require.Equal(t, PlanEventTaskStart, actual.Items[0].Type)
+	require.Equal(t, "8", actual.Items[0].Details["job-id"])
+	require.Equal(t, "4", actual.NextEvents.Event)
+
+	// Test with start
+	setupGetResponder(t, testURL, "start=2", "plan-job-events-response.json")
+	actual, err = orchClient.PlanJobEvents("1234", "2")
+	require.Nil(t, err)
+	require.Equal(t, PlanEventPlanFinished, actual.Items[2].Type)
+
+	// Test error
+	setupErrorResponder(t, testURL)
+	actual, err = orchClient.PlanJobEvents("1234", "")
+	require.Nil(t, actual)
+	require.Equal(t, expectedErrorAt(testURL), err)
+
+	// Test HTTP error
+	setupResponderWithStatusCodeAndBody(t, testURL, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
+	actual, err = orchClient.PlanJobEvents("1234", "")
+	testHTTPError(t, actual, err, http.StatusNotFound)
+}
+
+func setupPlanJobSequenceResponder(jobID string, planJobs []PlanJob) {
+	httpmock.Reset()
+	calls := 0
+	httpmock.RegisterResponder(http.MethodGet, orchHostURL+strings.ReplaceAll(orchPlanJob, "{job-id}", jobID),
+		func(req *http.Request) (*http.Response, error) {
+			planJob := planJobs[len(planJobs)-1]
+			if calls < len(planJobs) {
+				planJob = planJobs[calls]
+			}
+			calls++
+			return httpmock.NewJsonResponse(http.StatusOK, planJob)
+		},
+	)
+}
+
+func TestWaitForPlanJob(t *testing.T) {
+	setupPlanJobSequenceResponder("1234", []PlanJob{
+		{Name: "1234", State: PlanJobStatePending},
+		{Name: "1234", State: PlanJobStateRunning},
+		{Name: "1234", State: PlanJobStateSuccess, Result: "done"},
+	})
+	actual, err := orchClient.WaitForPlanJob(context.Background(), "1234", testPollPolicy)
+	require.Nil(t, err)
+	require.Equal(t, "done", actual.Result)
+
+	// Test failure
+	setupPlanJobSequenceResponder("1234", []PlanJob{
+		{Name: "1234", State: PlanJobStateRunning},
+		{Name: "1234", State: PlanJobStateFailure, Options: PlanJobOptions{PlanName: "myplan"}},
+	})
+	actual, err = orchClient.WaitForPlanJob(context.Background(), "1234", testPollPolicy)
+	var failedErr *PlanJobFailedError
+	require.True(t, errors.As(err, &failedErr))
+	require.Equal(t, actual, failedErr.PlanJob)
+	require.Equal(t, "plan job 1234 (myplan) failure", err.Error())
+
+	// Test error
+	setupErrorResponder(t, strings.ReplaceAll(orchPlanJob, "{job-id}", "1234"))
+	actual, err = orchClient.WaitForPlanJob(context.Background(), "1234", testPollPolicy)
+	require.Nil(t, actual)
+	require.Equal(t, expectedErrorAt(strings.ReplaceAll(orchPlanJob, "{job-id}", "1234")), err)
+}
@@ -1,6 +1,7 @@
 package orch
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -15,8 +16,13 @@ var plansRegex = regexp.MustCompile(`http.*\/orchestrator\/v1\/plans\/(.*)/(.*)`
 
 // Plans lists all known plans in a given environment (GET /plans)
 func (c *Client) Plans(environment string) (*Plans, error) {
+	return c.PlansCtx(context.Background(), environment)
+}
+
+// PlansCtx is Plans with a context that cancels the request when done
+func (c *Client) PlansCtx(ctx context.Context, environment string) (*Plans, error) {
 	payload := &Plans{}
-	req := c.resty.R().SetResult(payload)
+	req := c.resty.R().SetContext(ctx).SetResult(payload)
 	if environment != "" {
 		req.SetQueryParam("environment", environment)
 	}
@@ -31,19 +37,30 @@ func (c *Client) Plans(environment string) (*Plans, error) {
 
 // PlanByID extracts the module and planname from the supplied ID and calls Plan(...)
 func (c *Client) PlanByID(environment, planID string) (*Plan, error) {
+	return c.PlanByIDCtx(context.Background(), environment, planID)
+}
+
+// PlanByIDCtx is PlanByID with a context that cancels the request when done
+func (c *Client) PlanByIDCtx(ctx context.Context, environment, planID string) (*Plan, error) {
 	results := plansRegex.FindStringSubmatch(planID)
 	if len(results) != 3 {
 		return nil, fmt.Errorf("unknown plan ID format: %s", planID)
 	}
 	module := results[1]
 	planname := results[2]
-	return c.Plan(environment, module, planname)
+	return c.PlanCtx(ctx, environment, module, planname)
 }
 
 // Plan returns data about the specified plan, including metadata (GET /plans/:module/:planname)
 func (c *Client) Plan(environment, module, planname string) (*Plan, error) {
+	return c.PlanCtx(context.Background(), environment, module, planname)
+}
+
+// PlanCtx is Plan with a context that cancels the request when done
+func (c *Client) PlanCtx(ctx context.Context, environment, module, planname string) (*Plan, error) {
 	payload := &Plan{}
 	req := c.resty.R().
+		SetContext(ctx).
 		SetResult(payload).
 		SetPathParams(map[string]string{
 			"module":   module,
@@ -27,7 +27,7 @@ func TestPlans(t *testing.T) {
 	setupErrorResponder(t, orchPlans)
 	actual, err = orchClient.Plans("")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchPlans), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchPlans, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
@@ -55,7 +55,7 @@ func TestPlan(t *testing.T) {
 	setupErrorResponder(t, orchPlanPackageInstall)
 	actual, err = orchClient.Plan("myenv", "package", "install")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchPlanPackageInstall), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchPlanPackageInstall, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
@@ -85,7 +85,7 @@ func TestPlanByID(t *testing.T) {
 	setupErrorResponder(t, orchPlanPackageUpgrade)
 	actual, err = orchClient.PlanByID("myenv", id)
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchPlanPackageUpgrade), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchPlanPackageUpgrade, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
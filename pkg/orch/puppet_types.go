@@ -0,0 +1,861 @@
+package orch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PuppetType is a parsed Puppet data type expression such as Optional[String[1]] or Enum[start, stop],
+// as found in the parameters of task and plan metadata.
+// Types the parser does not know about (e.g. module type aliases such as Stdlib::Absolutepath) match any value except undef.
+type PuppetType struct {
+	expr string
+	m    matcher
+}
+
+// ParsePuppetType parses a Puppet data type expression
+func ParsePuppetType(expr string) (*PuppetType, error) {
+	p := &typeParser{lexer: typeLexer{input: expr}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	node, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %q after type", p.tok.text)
+	}
+	m, err := buildMatcher(node)
+	if err != nil {
+		return nil, fmt.Errorf("puppet type %q: %w", expr, err)
+	}
+	return &PuppetType{expr: strings.TrimSpace(expr), m: m}, nil
+}
+
+// String returns the type expression as it was parsed
+func (t *PuppetType) String() string {
+	return t.expr
+}
+
+// Matches returns true if the value is an instance of the type. The value is compared as it would be
+// sent to the orchestrator, i.e. after JSON encoding.
+func (t *PuppetType) Matches(v interface{}) bool {
+	normalized, err := normalizeValue(v)
+	if err != nil {
+		return false
+	}
+	return t.m.match(normalized)
+}
+
+// Optional returns true if the type accepts undef, meaning a parameter of this type can be omitted
+func (t *PuppetType) Optional() bool {
+	return t.m.match(nil)
+}
+
+// normalizeValue round trips the value through JSON so that matchers only need to handle the types
+// produced by encoding/json: nil, bool, string, json.Number, []interface{} and map[string]interface{}.
+func normalizeValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var normalized interface{}
+	err = d.Decode(&normalized)
+	return normalized, err
+}
+
+// typeName returns the Puppet type name of a normalized value, used in error messages
+func typeName(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return "Undef"
+	case bool:
+		return "Boolean"
+	case string:
+		return "String"
+	case json.Number:
+		if isInteger(value) {
+			return "Integer"
+		}
+		return "Float"
+	case []interface{}:
+		return "Array"
+	case map[string]interface{}:
+		return "Hash"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func isInteger(n json.Number) bool {
+	_, err := strconv.ParseInt(string(n), 10, 64)
+	return err == nil
+}
+
+type matcher interface {
+	match(v interface{}) bool
+}
+
+type anyMatcher struct{}
+
+func (anyMatcher) match(interface{}) bool { return true }
+
+type undefMatcher struct{}
+
+func (undefMatcher) match(v interface{}) bool { return v == nil }
+
+type notUndefMatcher struct{ inner matcher }
+
+func (m notUndefMatcher) match(v interface{}) bool { return v != nil && m.inner.match(v) }
+
+type optionalMatcher struct{ inner matcher }
+
+func (m optionalMatcher) match(v interface{}) bool { return v == nil || m.inner.match(v) }
+
+type variantMatcher struct{ types []matcher }
+
+func (m variantMatcher) match(v interface{}) bool {
+	for _, t := range m.types {
+		if t.match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+type scalarMatcher struct{}
+
+func (scalarMatcher) match(v interface{}) bool {
+	switch v.(type) {
+	case string, bool, json.Number:
+		return true
+	}
+	return false
+}
+
+type dataMatcher struct{}
+
+func (m dataMatcher) match(v interface{}) bool {
+	switch value := v.(type) {
+	case nil, string, bool, json.Number:
+		return true
+	case []interface{}:
+		for _, e := range value {
+			if !m.match(e) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		for _, e := range value {
+			if !m.match(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+type booleanMatcher struct{}
+
+func (booleanMatcher) match(v interface{}) bool {
+	_, ok := v.(bool)
+	return ok
+}
+
+// sizeRange is an inclusive range of lengths, max < 0 is unbounded
+type sizeRange struct{ min, max int64 }
+
+func (r sizeRange) contains(n int) bool {
+	return int64(n) >= r.min && (r.max < 0 || int64(n) <= r.max)
+}
+
+var unbounded = sizeRange{min: 0, max: -1}
+
+type stringMatcher struct{ size sizeRange }
+
+func (m stringMatcher) match(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && m.size.contains(utf8.RuneCountInString(s))
+}
+
+type numberMatcher struct {
+	integer, float bool
+	min, max       *float64
+}
+
+func (m numberMatcher) match(v interface{}) bool {
+	n, ok := v.(json.Number)
+	if !ok {
+		return false
+	}
+	if isInteger(n) {
+		if !m.integer {
+			return false
+		}
+	} else if !m.float {
+		return false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return false
+	}
+	return (m.min == nil || f >= *m.min) && (m.max == nil || f <= *m.max)
+}
+
+type enumMatcher struct{ values []string }
+
+func (m enumMatcher) match(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	for _, value := range m.values {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
+
+type patternMatcher struct{ patterns []*regexp.Regexp }
+
+func (m patternMatcher) match(v interface{}) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return len(m.patterns) == 0
+}
+
+type arrayMatcher struct {
+	elem matcher
+	size sizeRange
+}
+
+func (m arrayMatcher) match(v interface{}) bool {
+	a, ok := v.([]interface{})
+	if !ok || !m.size.contains(len(a)) {
+		return false
+	}
+	for _, e := range a {
+		if !m.elem.match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+type hashMatcher struct {
+	key, value matcher
+	size       sizeRange
+}
+
+func (m hashMatcher) match(v interface{}) bool {
+	h, ok := v.(map[string]interface{})
+	if !ok || !m.size.contains(len(h)) {
+		return false
+	}
+	for k, e := range h {
+		if !m.key.match(k) || !m.value.match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// tupleMatcher matches arrays element by element, elements beyond the listed types must match the last type
+type tupleMatcher struct {
+	types []matcher
+	size  sizeRange
+}
+
+func (m tupleMatcher) match(v interface{}) bool {
+	a, ok := v.([]interface{})
+	if !ok || !m.size.contains(len(a)) {
+		return false
+	}
+	for i, e := range a {
+		t := m.types[len(m.types)-1]
+		if i < len(m.types) {
+			t = m.types[i]
+		}
+		if !t.match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+type structMember struct {
+	key      string
+	optional bool
+	value    matcher
+}
+
+type structMatcher struct{ members []structMember }
+
+func (m structMatcher) match(v interface{}) bool {
+	h, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for _, member := range m.members {
+		e, present := h[member.key]
+		if !present {
+			if !member.optional && !member.value.match(nil) {
+				return false
+			}
+			continue
+		}
+		if !member.value.match(e) {
+			return false
+		}
+	}
+	for k := range h {
+		if !m.hasMember(k) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m structMatcher) hasMember(key string) bool {
+	for _, member := range m.members {
+		if member.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// targetSpecMatcher matches Bolt's TargetSpec alias: a target name, a target hash or an array of either
+type targetSpecMatcher struct{}
+
+func (m targetSpecMatcher) match(v interface{}) bool {
+	switch value := v.(type) {
+	case string, map[string]interface{}:
+		return true
+	case []interface{}:
+		for _, e := range value {
+			if !m.match(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// typeNode is a type expression before it is turned into a matcher. Parameters are *typeNode, string,
+// json.Number, *regexp.Regexp, defaultParam or []hashEntry values.
+type typeNode struct {
+	name   string
+	params []interface{}
+}
+
+type defaultParam struct{}
+
+type hashEntry struct {
+	key, value interface{}
+}
+
+func buildMatcher(node *typeNode) (matcher, error) {
+	switch node.name {
+	case "Any":
+		return anyMatcher{}, nil
+	case "Data", "RichData":
+		return dataMatcher{}, nil
+	case "Undef":
+		return undefMatcher{}, nil
+	case "Scalar", "ScalarData":
+		return scalarMatcher{}, nil
+	case "Boolean":
+		return booleanMatcher{}, nil
+	case "String":
+		size, err := sizeParams(node.params)
+		return stringMatcher{size: size}, err
+	case "Integer", "Float", "Numeric":
+		m := numberMatcher{integer: node.name != "Float", float: node.name != "Integer"}
+		var err error
+		if m.min, m.max, err = boundParams(node.params); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case "Enum":
+		var values []string
+		for _, p := range node.params {
+			s, ok := p.(string)
+			if !ok {
+				return nil, fmt.Errorf("parameters of Enum must be strings")
+			}
+			values = append(values, s)
+		}
+		return enumMatcher{values: values}, nil
+	case "Pattern":
+		var patterns []*regexp.Regexp
+		for _, p := range node.params {
+			switch value := p.(type) {
+			case *regexp.Regexp:
+				patterns = append(patterns, value)
+			case string:
+				re, err := regexp.Compile(value)
+				if err != nil {
+					return nil, err
+				}
+				patterns = append(patterns, re)
+			default:
+				return nil, fmt.Errorf("parameters of Pattern must be regular expressions")
+			}
+		}
+		return patternMatcher{patterns: patterns}, nil
+	case "Optional", "NotUndef", "Sensitive":
+		inner, err := singleTypeParam(node)
+		if err != nil {
+			return nil, err
+		}
+		switch node.name {
+		case "Optional":
+			return optionalMatcher{inner: inner}, nil
+		case "NotUndef":
+			return notUndefMatcher{inner: inner}, nil
+		}
+		return inner, nil
+	case "Variant":
+		types, rest, err := typeParams(node.params)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) > 0 {
+			return nil, fmt.Errorf("parameters of Variant must be types")
+		}
+		return variantMatcher{types: types}, nil
+	case "Array":
+		types, rest, err := typeParams(node.params)
+		if err != nil {
+			return nil, err
+		}
+		if len(types) > 1 {
+			return nil, fmt.Errorf("an Array takes a single element type")
+		}
+		m := arrayMatcher{elem: anyMatcher{}}
+		if len(types) == 1 {
+			m.elem = types[0]
+		}
+		m.size, err = sizeParams(rest)
+		return m, err
+	case "Hash":
+		types, rest, err := typeParams(node.params)
+		if err != nil {
+			return nil, err
+		}
+		m := hashMatcher{key: anyMatcher{}, value: anyMatcher{}}
+		switch len(types) {
+		case 0:
+		case 2:
+			m.key, m.value = types[0], types[1]
+		default:
+			return nil, fmt.Errorf("a Hash takes a key and a value type")
+		}
+		m.size, err = sizeParams(rest)
+		return m, err
+	case "Tuple":
+		types, rest, err := typeParams(node.params)
+		if err != nil {
+			return nil, err
+		}
+		if len(types) == 0 {
+			return arrayMatcher{elem: anyMatcher{}, size: unbounded}, nil
+		}
+		size := sizeRange{min: int64(len(types)), max: int64(len(types))}
+		if len(rest) > 0 {
+			if size, err = sizeParams(rest); err != nil {
+				return nil, err
+			}
+		}
+		return tupleMatcher{types: types, size: size}, nil
+	case "Struct":
+		return buildStruct(node)
+	case "TargetSpec", "Boltlib::TargetSpec":
+		return targetSpecMatcher{}, nil
+	}
+	// Unknown types, usually type aliases defined in modules, are not validated beyond requiring a value,
+	// as an alias has to be wrapped in Optional to accept undef
+	return notUndefMatcher{inner: anyMatcher{}}, nil
+}
+
+func singleTypeParam(node *typeNode) (matcher, error) {
+	if len(node.params) != 1 {
+		return nil, fmt.Errorf("%s takes a single type parameter", node.name)
+	}
+	switch value := node.params[0].(type) {
+	case *typeNode:
+		return buildMatcher(value)
+	case string:
+		// Optional['value'] is shorthand for Optional[Enum['value']]
+		return enumMatcher{values: []string{value}}, nil
+	}
+	return nil, fmt.Errorf("%s takes a single type parameter", node.name)
+}
+
+// typeParams splits the leading type parameters from any trailing non-type parameters
+func typeParams(params []interface{}) ([]matcher, []interface{}, error) {
+	var types []matcher
+	for i, p := range params {
+		node, ok := p.(*typeNode)
+		if !ok {
+			return types, params[i:], nil
+		}
+		m, err := buildMatcher(node)
+		if err != nil {
+			return nil, nil, err
+		}
+		types = append(types, m)
+	}
+	return types, nil, nil
+}
+
+// sizeParams parses optional min and max integer parameters
+func sizeParams(params []interface{}) (sizeRange, error) {
+	size := unbounded
+	if len(params) > 2 {
+		return size, fmt.Errorf("too many size parameters")
+	}
+	for i, p := range params {
+		switch value := p.(type) {
+		case defaultParam:
+		case json.Number:
+			n, err := value.Int64()
+			if err != nil {
+				return size, fmt.Errorf("size must be an integer: %s", value)
+			}
+			if i == 0 {
+				size.min = n
+			} else {
+				size.max = n
+			}
+		default:
+			return size, fmt.Errorf("size must be an integer")
+		}
+	}
+	return size, nil
+}
+
+// boundParams parses optional min and max numeric parameters
+func boundParams(params []interface{}) (*float64, *float64, error) {
+	if len(params) > 2 {
+		return nil, nil, fmt.Errorf("too many range parameters")
+	}
+	bounds := make([]*float64, 2)
+	for i, p := range params {
+		switch value := p.(type) {
+		case defaultParam:
+		case json.Number:
+			f, err := value.Float64()
+			if err != nil {
+				return nil, nil, err
+			}
+			bounds[i] = &f
+		default:
+			return nil, nil, fmt.Errorf("range must be numeric")
+		}
+	}
+	return bounds[0], bounds[1], nil
+}
+
+func buildStruct(node *typeNode) (matcher, error) {
+	if len(node.params) != 1 {
+		return nil, fmt.Errorf("a Struct takes a single hash parameter")
+	}
+	entries, ok := node.params[0].([]hashEntry)
+	if !ok {
+		return nil, fmt.Errorf("a Struct takes a single hash parameter")
+	}
+	m := structMatcher{}
+	for _, entry := range entries {
+		member := structMember{}
+		switch key := entry.key.(type) {
+		case string:
+			member.key = key
+		case *typeNode:
+			if (key.name != "Optional" && key.name != "NotUndef") || len(key.params) != 1 {
+				return nil, fmt.Errorf("keys of a Struct must be strings, Optional[key] or NotUndef[key]")
+			}
+			name, ok := key.params[0].(string)
+			if !ok {
+				return nil, fmt.Errorf("keys of a Struct must be strings, Optional[key] or NotUndef[key]")
+			}
+			member.key = name
+			member.optional = key.name == "Optional"
+		default:
+			return nil, fmt.Errorf("keys of a Struct must be strings, Optional[key] or NotUndef[key]")
+		}
+		valueNode, ok := entry.value.(*typeNode)
+		if !ok {
+			return nil, fmt.Errorf("values of a Struct must be types")
+		}
+		value, err := buildMatcher(valueNode)
+		if err != nil {
+			return nil, err
+		}
+		member.value = value
+		m.members = append(m.members, member)
+	}
+	return m, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokString
+	tokNumber
+	tokRegex
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type typeLexer struct {
+	input string
+	pos   int
+}
+
+func (l *typeLexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+		l.pos++
+	}
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+
+	c := l.input[l.pos]
+	switch {
+	case c == '=' && strings.HasPrefix(l.input[l.pos:], "=>"):
+		l.pos += 2
+		return token{kind: tokPunct, text: "=>", pos: start}, nil
+	case strings.IndexByte("[]{},", c) >= 0:
+		l.pos++
+		return token{kind: tokPunct, text: string(c), pos: start}, nil
+	case c == '\'' || c == '"' || c == '/':
+		text, err := l.quoted(c)
+		if err != nil {
+			return token{}, err
+		}
+		kind := tokString
+		if c == '/' {
+			kind = tokRegex
+		}
+		return token{kind: kind, text: text, pos: start}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		l.pos++
+		for l.pos < len(l.input) && strings.IndexByte("0123456789.eE+-", l.input[l.pos]) >= 0 {
+			l.pos++
+		}
+		return token{kind: tokNumber, text: l.input[start:l.pos], pos: start}, nil
+	case c == '_' || c == ':' || unicode.IsLetter(rune(c)):
+		for l.pos < len(l.input) {
+			c := rune(l.input[l.pos])
+			if c != '_' && c != ':' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+				break
+			}
+			l.pos++
+		}
+		return token{kind: tokName, text: l.input[start:l.pos], pos: start}, nil
+	}
+	return token{}, fmt.Errorf("puppet type %q: unexpected character %q at offset %d", l.input, c, start)
+}
+
+// quoted reads a string or regex delimited by quote, handling backslash escapes
+func (l *typeLexer) quoted(quote byte) (string, error) {
+	start := l.pos
+	l.pos++
+	var sb strings.Builder
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		switch {
+		case c == '\\' && l.pos+1 < len(l.input):
+			next := l.input[l.pos+1]
+			// Regexes keep their escapes, strings only unescape the quote and backslash
+			if quote == '/' && next != '/' {
+				sb.WriteByte(c)
+			} else if quote != '/' && next != quote && next != '\\' {
+				sb.WriteByte(c)
+			}
+			sb.WriteByte(next)
+			l.pos += 2
+		case c == quote:
+			l.pos++
+			return sb.String(), nil
+		default:
+			sb.WriteByte(c)
+			l.pos++
+		}
+	}
+	return "", fmt.Errorf("puppet type %q: unterminated %c at offset %d", l.input, quote, start)
+}
+
+type typeParser struct {
+	lexer typeLexer
+	tok   token
+}
+
+func (p *typeParser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *typeParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("puppet type %q: %s at offset %d", p.lexer.input, fmt.Sprintf(format, args...), p.tok.pos)
+}
+
+func (p *typeParser) expect(punct string) error {
+	if p.tok.kind != tokPunct || p.tok.text != punct {
+		if p.tok.kind == tokEOF {
+			return p.errorf("expected %q but reached the end", punct)
+		}
+		return p.errorf("expected %q but found %q", punct, p.tok.text)
+	}
+	return p.advance()
+}
+
+// parseType parses Name or Name[param, ...]
+func (p *typeParser) parseType() (*typeNode, error) {
+	if p.tok.kind != tokName || !isTypeName(p.tok.text) {
+		return nil, p.errorf("expected a type name but found %q", p.tok.text)
+	}
+	node := &typeNode{name: strings.TrimPrefix(p.tok.text, "::")}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokPunct || p.tok.text != "[" {
+		return node, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	for {
+		if p.tok.kind == tokPunct && p.tok.text == "]" {
+			return node, p.advance()
+		}
+		param, err := p.parseParam()
+		if err != nil {
+			return nil, err
+		}
+		node.params = append(node.params, param)
+		if p.tok.kind == tokPunct && p.tok.text == "," {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+}
+
+func (p *typeParser) parseParam() (interface{}, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokName:
+		if isTypeName(tok.text) {
+			return p.parseType()
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if tok.text == "default" {
+			return defaultParam{}, nil
+		}
+		// Bare words are strings, e.g. Enum[start, stop]
+		return tok.text, nil
+	case tokString:
+		return tok.text, p.advance()
+	case tokNumber:
+		if _, err := strconv.ParseFloat(tok.text, 64); err != nil {
+			return nil, p.errorf("invalid number %q", tok.text)
+		}
+		return json.Number(tok.text), p.advance()
+	case tokRegex:
+		re, err := regexp.Compile(tok.text)
+		if err != nil {
+			return nil, p.errorf("invalid regular expression: %v", err)
+		}
+		return re, p.advance()
+	case tokPunct:
+		if tok.text == "{" {
+			return p.parseHash()
+		}
+	case tokEOF:
+		return nil, p.errorf("unexpected end of type")
+	}
+	return nil, p.errorf("unexpected %q", tok.text)
+}
+
+// parseHash parses {key => value, ...}
+func (p *typeParser) parseHash() ([]hashEntry, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	entries := []hashEntry{}
+	for {
+		if p.tok.kind == tokPunct && p.tok.text == "}" {
+			return entries, p.advance()
+		}
+		key, err := p.parseParam()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("=>"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseParam()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, hashEntry{key: key, value: value})
+		if p.tok.kind == tokPunct && p.tok.text == "," {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := p.expect("}"); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+}
+
+// isTypeName returns true for capitalised names such as String or Stdlib::Absolutepath
+func isTypeName(name string) bool {
+	name = strings.TrimPrefix(name, "::")
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
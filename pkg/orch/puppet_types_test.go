@@ -0,0 +1,87 @@
+package orch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePuppetType(t *testing.T) {
+	tests := []struct {
+		expr     string
+		matches  []interface{}
+		rejects  []interface{}
+		optional bool
+	}{
+		{expr: "Any", matches: []interface{}{nil, "a", 1, []string{}}, optional: true},
+		{expr: "String", matches: []interface{}{"", "abc"}, rejects: []interface{}{nil, 1, true}},
+		{expr: "String[1]", matches: []interface{}{"a"}, rejects: []interface{}{""}},
+		{expr: "String[1, 3]", matches: []interface{}{"abc", "ééé"}, rejects: []interface{}{"abcd"}},
+		{expr: "Integer", matches: []interface{}{1, -5, int64(1) << 40}, rejects: []interface{}{1.5, "1"}},
+		{expr: "Integer[0, default]", matches: []interface{}{0, 100}, rejects: []interface{}{-1}},
+		{expr: "Integer[1, 10]", matches: []interface{}{1, 10}, rejects: []interface{}{0, 11}},
+		{expr: "Float[0.5]", matches: []interface{}{0.5, 2.25}, rejects: []interface{}{0.25, 1}},
+		{expr: "Numeric", matches: []interface{}{1, 1.5}, rejects: []interface{}{"1"}},
+		{expr: "Boolean", matches: []interface{}{true, false}, rejects: []interface{}{"true", 0}},
+		{expr: "Optional[String[1]]", matches: []interface{}{nil, "a"}, rejects: []interface{}{"", 1}, optional: true},
+		{expr: "Enum[start,stop]", matches: []interface{}{"start", "stop"}, rejects: []interface{}{"restart", nil}},
+		{expr: "Enum['a b', \"c\"]", matches: []interface{}{"a b", "c"}, rejects: []interface{}{"a"}},
+		{expr: `Pattern[/^\d+$/, /^x/]`, matches: []interface{}{"123", "xyz"}, rejects: []interface{}{"abc", 123}},
+		{expr: "Variant[String, Integer]", matches: []interface{}{"a", 1}, rejects: []interface{}{true}},
+		{expr: "Array", matches: []interface{}{[]interface{}{1, "a"}}, rejects: []interface{}{"a"}},
+		{expr: "Array[String, 1]", matches: []interface{}{[]string{"a"}}, rejects: []interface{}{[]string{}, []int{1}}},
+		{expr: "Hash[String, Integer]", matches: []interface{}{map[string]int{"a": 1}}, rejects: []interface{}{map[string]string{"a": "b"}}},
+		{expr: "Hash[Enum[a], Any, 1, 1]", matches: []interface{}{map[string]int{"a": 1}}, rejects: []interface{}{map[string]int{"b": 1}, map[string]int{}}},
+		{expr: "Tuple[String, Integer]", matches: []interface{}{[]interface{}{"a", 1}}, rejects: []interface{}{[]interface{}{"a"}, []interface{}{1, "a"}}},
+		{expr: "Tuple[String, Integer, 1, 3]", matches: []interface{}{[]interface{}{"a"}, []interface{}{"a", 1, 2}}, rejects: []interface{}{[]interface{}{"a", 1, "b"}}},
+		{
+			expr:    "Struct[{name => String, Optional['port'] => Integer, tags => Optional[Array[String]]}]",
+			matches: []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "a", "port": 22, "tags": []string{"x"}}},
+			rejects: []interface{}{map[string]interface{}{}, map[string]interface{}{"name": "a", "extra": 1}, map[string]interface{}{"name": "a", "port": "22"}},
+		},
+		{expr: "NotUndef[Any]", matches: []interface{}{"a"}, rejects: []interface{}{nil}},
+		{expr: "Sensitive[String]", matches: []interface{}{"secret"}, rejects: []interface{}{1}},
+		{expr: "Data", matches: []interface{}{nil, map[string]interface{}{"a": []int{1}}}, optional: true},
+		{expr: "Scalar", matches: []interface{}{"a", 1, true}, rejects: []interface{}{nil, []int{}}},
+		{expr: "TargetSpec", matches: []interface{}{"node1", []string{"node1", "node2"}}, rejects: []interface{}{1}},
+		{expr: "Stdlib::Absolutepath", matches: []interface{}{"/tmp", 1}, rejects: []interface{}{nil}},
+		{expr: "Optional[Stdlib::Absolutepath]", matches: []interface{}{nil, "/tmp"}, optional: true},
+	}
+
+	for _, test := range tests {
+		pt, err := ParsePuppetType(test.expr)
+		require.Nil(t, err, test.expr)
+		require.Equal(t, test.expr, pt.String())
+		require.Equal(t, test.optional, pt.Optional(), test.expr)
+		for _, v := range test.matches {
+			require.True(t, pt.Matches(v), "%s should match %#v", test.expr, v)
+		}
+		for _, v := range test.rejects {
+			require.False(t, pt.Matches(v), "%s should not match %#v", test.expr, v)
+		}
+	}
+}
+
+func TestParsePuppetTypeErrors(t *testing.T) {
+	tests := map[string]string{
+		"":                  `puppet type "": expected a type name but found "" at offset 0`,
+		"string":            `puppet type "string": expected a type name but found "string" at offset 0`,
+		"String[1":          `puppet type "String[1": expected "]" but reached the end at offset 8`,
+		"String[1] x":       `puppet type "String[1] x": unexpected "x" after type at offset 10`,
+		"Enum['a":           `puppet type "Enum['a": unterminated ' at offset 5`,
+		"Pattern[/(/]":      `puppet type "Pattern[/(/]": invalid regular expression: error parsing regexp: missing closing ): ` + "`(`" + ` at offset 8`,
+		"Enum[String]":      `puppet type "Enum[String]": parameters of Enum must be strings`,
+		"Array[String, a]":  `puppet type "Array[String, a]": size must be an integer`,
+		"Struct[String]":    `puppet type "Struct[String]": a Struct takes a single hash parameter`,
+		"Optional[1]":       `puppet type "Optional[1]": Optional takes a single type parameter`,
+		"Integer[a]":        `puppet type "Integer[a]": range must be numeric`,
+		"Hash[String]":      `puppet type "Hash[String]": a Hash takes a key and a value type`,
+		"Variant[String,1]": `puppet type "Variant[String,1]": parameters of Variant must be types`,
+		"String[%]":         `puppet type "String[%]": unexpected character '%' at offset 7`,
+	}
+	for expr, expected := range tests {
+		pt, err := ParsePuppetType(expr)
+		require.Nil(t, pt, expr)
+		require.EqualError(t, err, expected, expr)
+	}
+}
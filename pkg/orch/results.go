@@ -0,0 +1,86 @@
+package orch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TaskResult is the result of a task run on a single node, split into its parts
+// Output	The raw output of the task (_output), set when the task did not print a JSON object.
+// Value	The structured output of the task, without the _output and _error keys.
+// Error	The error reported by the task or the orchestrator (_error), nil if the task succeeded.
+type TaskResult struct {
+	Output string
+	Value  map[string]interface{}
+	Error  *TaskError
+}
+
+// TaskError is the _error object of a failed task
+type TaskError struct {
+	Kind      string                 `json:"kind"`
+	Msg       string                 `json:"msg"`
+	IssueCode string                 `json:"issue_code,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+func (te *TaskError) Error() string {
+	return fmt.Sprintf("%s: %s", te.Kind, te.Msg)
+}
+
+// ExitCode returns the exit code of the task. A successful task exited with 0, a failed task returns the
+// exitcode from the error details. ok is false if the task failed without reporting an exit code, e.g.
+// because it could not be started.
+func (tr *TaskResult) ExitCode() (code int, ok bool) {
+	if tr.Error == nil {
+		return 0, true
+	}
+	switch exitcode := tr.Error.Details["exitcode"].(type) {
+	case float64:
+		return int(exitcode), true
+	case json.Number:
+		n, err := exitcode.Int64()
+		return int(n), err == nil
+	}
+	return 0, false
+}
+
+// TaskResult decodes the result of a task job on this node
+func (jn *JobNode) TaskResult() (*TaskResult, error) {
+	result := &TaskResult{Value: map[string]interface{}{}}
+	for k, v := range jn.Result {
+		switch k {
+		case "_output":
+			output, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("node %s: _output is a %T not a string", jn.Name, v)
+			}
+			result.Output = output
+		case "_error":
+			result.Error = &TaskError{}
+			if err := decodeInto(v, result.Error); err != nil {
+				return nil, fmt.Errorf("node %s: decoding _error: %w", jn.Name, err)
+			}
+		default:
+			result.Value[k] = v
+		}
+	}
+	return result, nil
+}
+
+// DecodeResult decodes the result of this node into v, which should be a pointer to a struct or map
+// matching the structured output of the task
+func (jn *JobNode) DecodeResult(v interface{}) error {
+	if err := decodeInto(jn.Result, v); err != nil {
+		return fmt.Errorf("node %s: decoding result: %w", jn.Name, err)
+	}
+	return nil
+}
+
+// decodeInto converts a decoded JSON value into v by re-encoding it
+func decodeInto(value interface{}, v interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
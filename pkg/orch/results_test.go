@@ -0,0 +1,80 @@
+package orch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskResult(t *testing.T) {
+	setupGetResponder(t, strings.ReplaceAll(orchJobNodes, "{job-id}", "3"), "", "jobs-nodes-task-response.json")
+	nodes, err := orchClient.JobNodes("3")
+	require.Nil(t, err)
+	require.Len(t, nodes.Items, 3)
+
+	// Test structured output
+	result, err := nodes.Items[0].TaskResult()
+	require.Nil(t, err)
+	require.Equal(t, &TaskResult{Value: map[string]interface{}{"status": "up to date", "version": "1.1.1-1ubuntu2.1~18.04.5"}}, result)
+	code, ok := result.ExitCode()
+	require.True(t, ok)
+	require.Equal(t, 0, code)
+
+	// Test raw output
+	result, err = nodes.Items[1].TaskResult()
+	require.Nil(t, err)
+	require.Equal(t, &TaskResult{Output: "hello world\n", Value: map[string]interface{}{}}, result)
+
+	// Test error
+	result, err = nodes.Items[2].TaskResult()
+	require.Nil(t, err)
+	require.Equal(t, "command not found\n", result.Output)
+	require.Equal(t, &TaskError{
+		Kind:      "puppetlabs.tasks/task-error",
+		Msg:       "The task failed with exit code 127",
+		IssueCode: "TASK_ERROR",
+		Details:   map[string]interface{}{"exitcode": float64(127)},
+	}, result.Error)
+	require.EqualError(t, result.Error, "puppetlabs.tasks/task-error: The task failed with exit code 127")
+	code, ok = result.ExitCode()
+	require.True(t, ok)
+	require.Equal(t, 127, code)
+}
+
+func TestTaskResultWithoutExitCode(t *testing.T) {
+	node := JobNode{Name: "node1", Result: map[string]interface{}{
+		"_error": map[string]interface{}{"kind": "puppetlabs.tasks/connection-error", "msg": "unreachable"},
+	}}
+	result, err := node.TaskResult()
+	require.Nil(t, err)
+	_, ok := result.ExitCode()
+	require.False(t, ok)
+
+	node.Result = map[string]interface{}{"_output": 1}
+	_, err = node.TaskResult()
+	require.EqualError(t, err, "node node1: _output is a int not a string")
+
+	node.Result = map[string]interface{}{"_error": "oops"}
+	_, err = node.TaskResult()
+	require.Error(t, err)
+}
+
+func TestDecodeResult(t *testing.T) {
+	node := JobNode{Name: "node1", Result: map[string]interface{}{
+		"status":  "out of date",
+		"version": "1.1.0g-2ubuntu4.1",
+	}}
+	var pkg struct {
+		Status  string `json:"status"`
+		Version string `json:"version"`
+	}
+	require.Nil(t, node.DecodeResult(&pkg))
+	require.Equal(t, "out of date", pkg.Status)
+	require.Equal(t, "1.1.0g-2ubuntu4.1", pkg.Version)
+
+	var wrong struct {
+		Status int `json:"status"`
+	}
+	require.Error(t, node.DecodeResult(&wrong))
+}
@@ -0,0 +1,93 @@
+package orch
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	orchScheduledJobs = "/orchestrator/v1/scheduled_jobs"
+	orchScheduledJob  = "/orchestrator/v1/scheduled_jobs/{job-id}"
+)
+
+// ScheduledJobs lists the scheduled jobs known to the orchestrator, pagination may be nil (GET /scheduled_jobs)
+func (c *Client) ScheduledJobs(pagination *Pagination) (*ScheduledJobs, error) {
+	return c.ScheduledJobsCtx(context.Background(), pagination)
+}
+
+// ScheduledJobsCtx is ScheduledJobs with a context that cancels the request when done
+func (c *Client) ScheduledJobsCtx(ctx context.Context, pagination *Pagination) (*ScheduledJobs, error) {
+	payload := &ScheduledJobs{}
+	req := c.resty.R().SetContext(ctx).SetResult(&payload)
+	if pagination != nil {
+		req.SetQueryParams(pagination.toParams())
+	}
+	r, err := req.Get(orchScheduledJobs)
+
+	if err = ProcessError(r, err, orchScheduledJobs); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// ScheduledJob returns the details of a given scheduled job (GET /scheduled_jobs/:job-id)
+func (c *Client) ScheduledJob(jobID string) (*ScheduledJob, error) {
+	return c.ScheduledJobCtx(context.Background(), jobID)
+}
+
+// ScheduledJobCtx is ScheduledJob with a context that cancels the request when done
+func (c *Client) ScheduledJobCtx(ctx context.Context, jobID string) (*ScheduledJob, error) {
+	payload := &ScheduledJob{}
+	r, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&payload).
+		SetPathParams(map[string]string{"job-id": jobID}).
+		Get(orchScheduledJob)
+
+	if err = ProcessError(r, err, strings.ReplaceAll(orchScheduledJob, "{job-id}", jobID)); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// DeleteScheduledJob deletes a given scheduled job (DELETE /scheduled_jobs/:job-id)
+func (c *Client) DeleteScheduledJob(jobID string) error {
+	return c.DeleteScheduledJobCtx(context.Background(), jobID)
+}
+
+// DeleteScheduledJobCtx is DeleteScheduledJob with a context that cancels the request when done
+func (c *Client) DeleteScheduledJobCtx(ctx context.Context, jobID string) error {
+	r, err := c.resty.R().
+		SetContext(ctx).
+		SetPathParams(map[string]string{"job-id": jobID}).
+		Delete(orchScheduledJob)
+
+	return ProcessError(r, err, strings.ReplaceAll(orchScheduledJob, "{job-id}", jobID))
+}
+
+// ScheduledJobs contains data about all scheduled jobs
+type ScheduledJobs struct {
+	Items      []ScheduledJob `json:"items"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// ScheduledJob contains data about a single scheduled job
+type ScheduledJob struct {
+	ID              string                 `json:"id"`
+	Name            string                 `json:"name"`
+	Type            string                 `json:"type"`
+	Environment     string                 `json:"environment"`
+	Owner           map[string]interface{} `json:"owner"`
+	Description     string                 `json:"description"`
+	ScheduledTime   string                 `json:"scheduled_time"`
+	NextRunTime     string                 `json:"next_run_time"`
+	ScheduleOptions *ScheduleOptions       `json:"schedule_options"`
+	Input           ScheduledJobInput      `json:"input"`
+}
+
+// ScheduledJobInput is the task, parameters and scope the scheduled job will run with
+type ScheduledJobInput struct {
+	Name       string                 `json:"name"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Scope      Scope                  `json:"scope"`
+}
@@ -0,0 +1,92 @@
+package orch
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledJobs(t *testing.T) {
+	// Test success
+	setupGetResponder(t, orchScheduledJobs, "", "scheduled-jobs-response.json")
+	actual, err := orchClient.ScheduledJobs(nil)
+	require.Nil(t, err)
+	require.Len(t, actual.Items, 1)
+	require.Equal(t, expectedScheduledJobInput, actual.Items[0].Input)
+	require.Equal(t, Pagination{Limit: 20, Total: 1, OrderBy: "next_run_time", Order: "asc", Type: "task"}, actual.Pagination)
+
+	// Test with pagination, only a request with exactly these parameters gets a response
+	setupGetResponder(t, orchScheduledJobs, "limit=20&offset=20&order_by=next_run_time&order=desc&type=task", "scheduled-jobs-response.json")
+	actual, err = orchClient.ScheduledJobs(&Pagination{Limit: 20, Offset: 20, OrderBy: "next_run_time", Order: "desc", Type: "task"})
+	require.Nil(t, err)
+	require.Len(t, actual.Items, 1)
+
+	// Test error
+	setupErrorResponder(t, orchScheduledJobs)
+	actual, err = orchClient.ScheduledJobs(nil)
+	require.Nil(t, actual)
+	require.Equal(t, expectedErrorAt(orchScheduledJobs), err)
+
+	// Test HTTP error
+	setupResponderWithStatusCodeAndBody(t, orchScheduledJobs, http.StatusForbidden, []byte(`{"StatusCode": 400}`))
+	actual, err = orchClient.ScheduledJobs(nil)
+	testHTTPError(t, actual, err, http.StatusForbidden)
+}
+
+func TestScheduledJob(t *testing.T) {
+	testURL := strings.ReplaceAll(orchScheduledJob, "{job-id}", "2")
+
+	// Test success
+	setupGetResponder(t, testURL, "", "scheduled-job-response.json")
+	actual, err := orchClient.ScheduledJob("2")
+	require.Nil(t, err)
+	require.Equal(t, "2", actual.Name)
+	require.Equal(t, "2027-05-05T19:50:08Z", actual.NextRunTime)
+	require.Equal(t, expectedScheduledJobInput, actual.Input)
+	require.Equal(t, &ScheduleOptions{Interval: Interval{Units: "seconds", Value: 86400}}, actual.ScheduleOptions)
+
+	// Test error
+	setupErrorResponder(t, testURL)
+	actual, err = orchClient.ScheduledJob("2")
+	require.Nil(t, actual)
+	require.Equal(t, expectedErrorAt(testURL), err)
+
+	// Test HTTP error
+	setupResponderWithStatusCodeAndBody(t, testURL, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
+	actual, err = orchClient.ScheduledJob("2")
+	testHTTPError(t, actual, err, http.StatusNotFound)
+}
+
+func TestDeleteScheduledJob(t *testing.T) {
+	testURL := strings.ReplaceAll(orchScheduledJob, "{job-id}", "2")
+
+	// Test success
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodDelete, orchHostURL+testURL, httpmock.NewStringResponder(http.StatusNoContent, ""))
+	err := orchClient.DeleteScheduledJob("2")
+	require.Nil(t, err)
+
+	// Test error
+	setupErrorResponder(t, testURL)
+	err = orchClient.DeleteScheduledJob("2")
+	require.Equal(t, expectedErrorAt(testURL), err)
+
+	// Test HTTP error
+	setupResponderWithStatusCodeAndBody(t, testURL, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
+	err = orchClient.DeleteScheduledJob("2")
+	testHTTPError(t, nil, err, http.StatusNotFound)
+}
+
+var expectedScheduledJobInput = ScheduledJobInput{
+	Name: "package",
+	Parameters: map[string]interface{}{
+		"action":  "install",
+		"package": "httpd",
+	},
+	Scope: Scope{
+		Nodes: []string{"node1.example.com"},
+	},
+}
@@ -0,0 +1,109 @@
+package orch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/puppetlabs/go-pe-client/pkg/classifier"
+)
+
+// ErrInvalidScope is returned by Scope.Validate when the scope does not have exactly one field set
+var ErrInvalidScope = errors.New("orch: invalid scope")
+
+// NewNodesScope creates a scope targeting a list of nodes by certname
+func NewNodesScope(nodes ...string) Scope {
+	return Scope{Nodes: nodes}
+}
+
+// NewNodeGroupScope creates a scope targeting the nodes in a classifier node group
+func NewNodeGroupScope(groupID string) Scope {
+	return Scope{NodeGroup: groupID}
+}
+
+// NewQueryScope creates a scope targeting the nodes returned by a PuppetDB AST query,
+// e.g. ["from", "nodes", ["~", "certname", ".*"]]
+func NewQueryScope(query []interface{}) Scope {
+	return Scope{Query: query}
+}
+
+// NewPQLScope creates a scope targeting the nodes returned by a PQL query,
+// e.g. nodes[certname] { catalog_environment = "production" }
+func NewPQLScope(pql string) Scope {
+	return Scope{PQL: pql}
+}
+
+// NewGroupRulesScope creates a query scope targeting the nodes matching the rules of a classifier node group
+func NewGroupRulesScope(rules classifier.GroupRules) (Scope, error) {
+	if rules.Translated.NodesQueryFormat == nil {
+		return Scope{}, fmt.Errorf("%w: the node group has no rules", ErrInvalidScope)
+	}
+	return NewQueryScope([]interface{}{"from", "nodes", rules.Translated.NodesQueryFormat}), nil
+}
+
+// Validate checks that exactly one field of the scope is set
+func (s Scope) Validate() error {
+	var set []string
+	if s.Application != "" {
+		set = append(set, "application")
+	}
+	if len(s.Nodes) > 0 {
+		set = append(set, "nodes")
+	}
+	if len(s.Query) > 0 {
+		set = append(set, "query")
+	}
+	if s.PQL != "" {
+		set = append(set, "pql")
+	}
+	if s.NodeGroup != "" {
+		set = append(set, "node_group")
+	}
+
+	switch len(set) {
+	case 0:
+		return fmt.Errorf("%w: no field is set", ErrInvalidScope)
+	case 1:
+		return nil
+	}
+	return fmt.Errorf("%w: only one field can be set but found %s", ErrInvalidScope, strings.Join(set, ", "))
+}
+
+// scopeJSON has the same fields as Scope, it is used to avoid recursing into the custom (un)marshalers
+type scopeJSON struct {
+	Application string      `json:"application,omitempty"`
+	Nodes       []string    `json:"nodes,omitempty"`
+	Query       interface{} `json:"query,omitempty"`
+	NodeGroup   string      `json:"node_group,omitempty"`
+}
+
+// MarshalJSON sends PQL as a string query and Query as an AST query
+func (s Scope) MarshalJSON() ([]byte, error) {
+	out := scopeJSON{Application: s.Application, Nodes: s.Nodes, NodeGroup: s.NodeGroup}
+	if len(s.Query) > 0 {
+		out.Query = s.Query
+	} else if s.PQL != "" {
+		out.Query = s.PQL
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes string queries into PQL and array queries into Query
+func (s *Scope) UnmarshalJSON(data []byte) error {
+	var in scopeJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*s = Scope{Application: in.Application, Nodes: in.Nodes, NodeGroup: in.NodeGroup}
+	switch query := in.Query.(type) {
+	case nil:
+	case string:
+		s.PQL = query
+	case []interface{}:
+		s.Query = query
+	default:
+		return fmt.Errorf("scope query must be a string or an array, got %T", query)
+	}
+	return nil
+}
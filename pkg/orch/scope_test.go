@@ -0,0 +1,67 @@
+package orch
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/puppetlabs/go-pe-client/pkg/classifier"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeConstructors(t *testing.T) {
+	require.Equal(t, Scope{Nodes: []string{"a", "b"}}, NewNodesScope("a", "b"))
+	require.Equal(t, Scope{NodeGroup: "00000000-0000-4000-8000-000000000000"}, NewNodeGroupScope("00000000-0000-4000-8000-000000000000"))
+	require.Equal(t, Scope{Query: []interface{}{"from", "nodes"}}, NewQueryScope([]interface{}{"from", "nodes"}))
+	require.Equal(t, Scope{PQL: "nodes[certname] {}"}, NewPQLScope("nodes[certname] {}"))
+
+	for _, scope := range []Scope{
+		NewNodesScope("a"),
+		NewNodeGroupScope("id"),
+		NewQueryScope([]interface{}{"from", "nodes"}),
+		NewPQLScope("nodes[certname] {}"),
+		{Application: "Wordpress_app[demo]"},
+	} {
+		require.Nil(t, scope.Validate())
+	}
+}
+
+func TestScopeValidate(t *testing.T) {
+	err := Scope{}.Validate()
+	require.ErrorIs(t, err, ErrInvalidScope)
+	require.EqualError(t, err, "orch: invalid scope: no field is set")
+
+	err = Scope{Nodes: []string{"a"}, PQL: "nodes {}", NodeGroup: "id"}.Validate()
+	require.ErrorIs(t, err, ErrInvalidScope)
+	require.EqualError(t, err, "orch: invalid scope: only one field can be set but found nodes, pql, node_group")
+}
+
+func TestNewGroupRulesScope(t *testing.T) {
+	rules := classifier.GroupRules{}
+	rules.Translated.NodesQueryFormat = []interface{}{"=", []interface{}{"fact", "os", "family"}, "RedHat"}
+
+	scope, err := NewGroupRulesScope(rules)
+	require.Nil(t, err)
+	require.Equal(t, NewQueryScope([]interface{}{"from", "nodes", rules.Translated.NodesQueryFormat}), scope)
+
+	data, err := json.Marshal(scope)
+	require.Nil(t, err)
+	require.JSONEq(t, `{"query": ["from", "nodes", ["=", ["fact", "os", "family"], "RedHat"]]}`, string(data))
+
+	_, err = NewGroupRulesScope(classifier.GroupRules{})
+	require.ErrorIs(t, err, ErrInvalidScope)
+}
+
+func TestScopeJSON(t *testing.T) {
+	data, err := json.Marshal(NewPQLScope(`nodes[certname] { catalog_environment = "production" }`))
+	require.Nil(t, err)
+	require.JSONEq(t, `{"query": "nodes[certname] { catalog_environment = \"production\" }"}`, string(data))
+
+	var scope Scope
+	require.Nil(t, json.Unmarshal([]byte(`{"query": "nodes {}"}`), &scope))
+	require.Equal(t, NewPQLScope("nodes {}"), scope)
+
+	require.Nil(t, json.Unmarshal([]byte(`{"query": ["from", "nodes"], "node_group": "id"}`), &scope))
+	require.Equal(t, Scope{Query: []interface{}{"from", "nodes"}, NodeGroup: "id"}, scope)
+
+	require.Error(t, json.Unmarshal([]byte(`{"query": 1}`), &scope))
+}
@@ -1,6 +1,7 @@
 package orch
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -15,8 +16,13 @@ var idRegex = regexp.MustCompile(`http.*\/orchestrator\/v1\/tasks\/(.*)/(.*)`)
 
 // Tasks lists all tasks in a given environment (GET /tasks)
 func (c *Client) Tasks(environment string) (*Tasks, error) {
+	return c.TasksCtx(context.Background(), environment)
+}
+
+// TasksCtx is Tasks with a context that cancels the request when done
+func (c *Client) TasksCtx(ctx context.Context, environment string) (*Tasks, error) {
 	payload := &Tasks{}
-	req := c.resty.R().SetResult(payload)
+	req := c.resty.R().SetContext(ctx).SetResult(payload)
 	if environment != "" {
 		req.SetQueryParam("environment", environment)
 	}
@@ -31,19 +37,30 @@ func (c *Client) Tasks(environment string) (*Tasks, error) {
 
 // TaskByID extracts the module and taskname from the supplied ID and calls Task(...)
 func (c *Client) TaskByID(environment, taskID string) (*Task, error) {
+	return c.TaskByIDCtx(context.Background(), environment, taskID)
+}
+
+// TaskByIDCtx is TaskByID with a context that cancels the request when done
+func (c *Client) TaskByIDCtx(ctx context.Context, environment, taskID string) (*Task, error) {
 	results := idRegex.FindStringSubmatch(taskID)
 	if len(results) != 3 {
 		return nil, fmt.Errorf("unknown task ID format: %s", taskID)
 	}
 	module := results[1]
 	taskname := results[2]
-	return c.Task(environment, module, taskname)
+	return c.TaskCtx(ctx, environment, module, taskname)
 }
 
 // Task returns data about a specified task, including metadata and file information. For the default task in a module, taskname is init. (GET /tasks/:module/:taskname)
 func (c *Client) Task(environment, module, taskname string) (*Task, error) {
+	return c.TaskCtx(context.Background(), environment, module, taskname)
+}
+
+// TaskCtx is Task with a context that cancels the request when done
+func (c *Client) TaskCtx(ctx context.Context, environment, module, taskname string) (*Task, error) {
 	payload := &Task{}
 	req := c.resty.R().
+		SetContext(ctx).
 		SetResult(payload).
 		SetPathParams(map[string]string{
 			"module":   module,
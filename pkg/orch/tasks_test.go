@@ -25,7 +25,7 @@ func TestTasks(t *testing.T) {
 	setupErrorResponder(t, orchTasks)
 	actual, err = orchClient.Tasks("")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchTasks), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchTasks, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
@@ -53,7 +53,7 @@ func TestTask(t *testing.T) {
 	setupErrorResponder(t, orchTaskFooBar)
 	actual, err = orchClient.Task("myenv", "foo", "bar")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchTaskFooBar), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchTaskFooBar, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
@@ -83,7 +83,7 @@ func TestTaskByID(t *testing.T) {
 	setupErrorResponder(t, orchTaskPackageUpgrade)
 	actual, err = orchClient.TaskByID("myenv", id)
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(orchTaskPackageUpgrade), err)
 
 	// Test HTTP error
 	setupResponderWithStatusCodeAndBody(t, orchTaskPackageUpgrade, http.StatusNotFound, []byte(`{"StatusCode": 400}`))
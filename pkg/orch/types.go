@@ -1,24 +1,79 @@
 package orch
 
-// Scope represents the scope of a job. Only a single field can be specified.
+import "encoding/json"
+
+// Scope represents the scope of a job. Only a single field can be specified, use one of the New*Scope
+// constructors and Validate to make sure of this. Query holds a PuppetDB AST query and PQL a PQL query,
+// both are sent as the query field.
 type Scope struct {
 	Application string        `json:"application,omitempty"`
 	Nodes       []string      `json:"nodes,omitempty"`
 	Query       []interface{} `json:"query,omitempty"`
+	PQL         string        `json:"-"`
 	NodeGroup   string        `json:"node_group,omitempty"`
 }
 
+// Transports supported for Bolt server targets
+const (
+	TransportSSH   = "ssh"
+	TransportWinRM = "winrm"
+)
+
+// Target describes nodes the orchestrator reaches over SSH or WinRM via Bolt server rather than the PXP agent.
+// The SSH and WinRM sections are only sent for the matching transport. Booleans that default to true in
+// Bolt are pointers so they can be explicitly disabled.
+type Target struct {
+	Hostnames      []string `json:"hostnames"`
+	Transport      string   `json:"transport"`
+	User           string   `json:"user,omitempty"`
+	Password       string   `json:"password,omitempty"`
+	Port           int      `json:"port,omitempty"`
+	ConnectTimeout int      `json:"connect-timeout,omitempty"`
+
+	// SSH options
+	PrivateKeyContent string `json:"private-key-content,omitempty"`
+	HostKeyCheck      *bool  `json:"host-key-check,omitempty"`
+	RunAs             string `json:"run-as,omitempty"`
+	SudoPassword      string `json:"sudo-password,omitempty"`
+	TTY               bool   `json:"tty,omitempty"`
+
+	// WinRM options
+	SSL        *bool    `json:"ssl,omitempty"`
+	SSLVerify  *bool    `json:"ssl-verify,omitempty"`
+	CACert     string   `json:"cacert,omitempty"`
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// targetJSON has the same fields as Target, it is used to avoid recursing into MarshalJSON
+type targetJSON Target
+
+// MarshalJSON leaves out the options of the transport the target does not use
+func (t Target) MarshalJSON() ([]byte, error) {
+	out := targetJSON(t)
+	if t.Transport != TransportSSH {
+		out.PrivateKeyContent, out.HostKeyCheck, out.RunAs, out.SudoPassword, out.TTY = "", nil, "", "", false
+	}
+	if t.Transport != TransportWinRM {
+		out.SSL, out.SSLVerify, out.CACert, out.Extensions = nil, nil, "", nil
+	}
+	return json.Marshal(out)
+}
+
 // Owner represents the owner of a job
 type Owner struct {
 	ID    string `json:"id"`
 	Login string `json:"login"`
 }
 
-// Pagination information about the current payload
+// Pagination information about the current payload. When passed to a list call the limit, offset and
+// ordering fields are sent as query parameters.
 type Pagination struct {
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
-	Total  int `json:"total"`
+	Limit   int    `json:"limit"`
+	Offset  int    `json:"offset"`
+	Total   int    `json:"total"`
+	OrderBy string `json:"order_by,omitempty"`
+	Order   string `json:"order,omitempty"`
+	Type    string `json:"type,omitempty"`
 }
 
 // Interval represents the time Interval for a task to run
@@ -5,12 +5,14 @@ import (
 	"reflect"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 )
 
-func newHTTPError(statusCode int, msg string) *HTTPError {
+func newHTTPError(statusCode int, msg, path string) *HTTPError {
 	return &HTTPError{
 		StatusCode: statusCode,
 		Msg:        msg,
+		Path:       path,
 	}
 }
 
@@ -24,21 +26,23 @@ func ProcessError(r *resty.Response, err error, errorString string) error {
 		} else {
 			message = r.Status()
 		}
+		path := apierror.RequestPath(r)
 		if orchErr, ok := r.Error().(*OrchestratorError); ok {
 			if reflect.DeepEqual(&OrchestratorError{}, orchErr) {
-				return newHTTPError(r.StatusCode(), message)
+				return newHTTPError(r.StatusCode(), message, path)
 			}
 
 			orchErr.StatusCode = r.StatusCode()
+			orchErr.Path = path
 			return orchErr
 		}
-		return newHTTPError(r.StatusCode(), message)
+		return newHTTPError(r.StatusCode(), message, path)
 	}
 
 	// Cater for an error which didn't come from a HTTP response. (e.g. host not listening)
 	if err != nil {
 		if len(errorString) > 0 {
-			return fmt.Errorf("%s", errorString)
+			return fmt.Errorf("%s: %w", errorString, err)
 		}
 		return err
 	}
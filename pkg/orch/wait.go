@@ -0,0 +1,125 @@
+package orch
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Job states reported by the orchestrator
+const (
+	JobStateNew      = "new"
+	JobStateReady    = "ready"
+	JobStateRunning  = "running"
+	JobStateStopping = "stopping"
+	JobStateStopped  = "stopped"
+	JobStateFinished = "finished"
+	JobStateFailed   = "failed"
+)
+
+// IsTerminalJobState returns true if the job state is one the orchestrator will not move on from
+func IsTerminalJobState(state string) bool {
+	switch state {
+	case JobStateFinished, JobStateFailed, JobStateStopped:
+		return true
+	}
+	return false
+}
+
+// PollPolicy controls how often a waiter polls the orchestrator.
+// Interval is the delay before the second poll, it is multiplied by Multiplier after every poll
+// up to MaxInterval. A Multiplier below 1 keeps the interval constant.
+type PollPolicy struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+}
+
+// DefaultPollPolicy polls every second, backing off to every 10 seconds for long running jobs
+func DefaultPollPolicy() PollPolicy {
+	return PollPolicy{
+		Interval:    time.Second,
+		MaxInterval: 10 * time.Second,
+		Multiplier:  1.5,
+	}
+}
+
+// next returns the interval to use after the current one
+func (p PollPolicy) next(current time.Duration) time.Duration {
+	if p.Multiplier <= 1 {
+		return current
+	}
+	next := time.Duration(float64(current) * p.Multiplier)
+	if p.MaxInterval > 0 && next > p.MaxInterval {
+		next = p.MaxInterval
+	}
+	return next
+}
+
+// JobProgressFunc is called by WaitForJob whenever the node states of the job change
+type JobProgressFunc func(job *Job)
+
+// JobFailedError is returned by WaitForJob when a job ends in any state other than finished
+type JobFailedError struct {
+	Job   *Job
+	Nodes *JobNodes
+}
+
+func (e *JobFailedError) Error() string {
+	return fmt.Sprintf("job %s %s: %d node(s) failed, %d node(s) errored",
+		e.Job.Name, e.Job.State, e.Job.NodeStates.Failed, e.Job.NodeStates.Errored)
+}
+
+// WaitForJob polls the given job (by name) until it reaches a terminal state and returns the final
+// job along with its nodes. progress may be nil. If the job fails or is stopped the job and nodes are
+// returned along with a *JobFailedError. If ctx is done before the job ends ctx.Err() is returned.
+func (c *Client) WaitForJob(ctx context.Context, jobID string, policy PollPolicy, progress JobProgressFunc) (*Job, *JobNodes, error) {
+	if policy.Interval <= 0 {
+		policy = DefaultPollPolicy()
+	}
+
+	var (
+		interval   = policy.Interval
+		lastStates *NodeStates
+	)
+	for {
+		job, err := c.JobCtx(ctx, jobID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if progress != nil && (lastStates == nil || *lastStates != job.NodeStates) {
+			progress(job)
+		}
+		states := job.NodeStates
+		lastStates = &states
+
+		if IsTerminalJobState(job.State) {
+			nodes, err := c.JobNodesCtx(ctx, jobID)
+			if err != nil {
+				return job, nil, err
+			}
+			if job.State != JobStateFinished {
+				return job, nodes, &JobFailedError{Job: job, Nodes: nodes}
+			}
+			return job, nodes, nil
+		}
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return nil, nil, err
+		}
+		interval = policy.next(interval)
+	}
+}
+
+// sleepContext waits for the given duration, returning early with ctx.Err() if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
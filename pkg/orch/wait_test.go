@@ -0,0 +1,99 @@
+package orch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+var testPollPolicy = PollPolicy{Interval: time.Millisecond}
+
+func setupJobSequenceResponder(t *testing.T, jobID string, jobs []Job) {
+	httpmock.Reset()
+	calls := 0
+	httpmock.RegisterResponder(http.MethodGet, orchHostURL+strings.ReplaceAll(orchJob, "{job-id}", jobID),
+		func(req *http.Request) (*http.Response, error) {
+			job := jobs[len(jobs)-1]
+			if calls < len(jobs) {
+				job = jobs[calls]
+			}
+			calls++
+			return httpmock.NewJsonResponse(http.StatusOK, job)
+		},
+	)
+	responder, err := httpmock.NewJsonResponder(http.StatusOK, JobNodes{Items: []JobNode{{Name: "node1.example.com", State: "finished"}}})
+	require.Nil(t, err)
+	httpmock.RegisterResponder(http.MethodGet, orchHostURL+strings.ReplaceAll(orchJobNodes, "{job-id}", jobID), responder)
+}
+
+func TestWaitForJob(t *testing.T) {
+	setupJobSequenceResponder(t, "123", []Job{
+		{Name: "123", State: JobStateNew},
+		{Name: "123", State: JobStateRunning, NodeStates: NodeStates{Running: 1}},
+		{Name: "123", State: JobStateRunning, NodeStates: NodeStates{Running: 1}},
+		{Name: "123", State: JobStateFinished, NodeStates: NodeStates{Finished: 1}},
+	})
+
+	var progress []NodeStates
+	job, nodes, err := orchClient.WaitForJob(context.Background(), "123", testPollPolicy, func(job *Job) {
+		progress = append(progress, job.NodeStates)
+	})
+	require.Nil(t, err)
+	require.Equal(t, JobStateFinished, job.State)
+	require.Len(t, nodes.Items, 1)
+	require.Equal(t, []NodeStates{{}, {Running: 1}, {Finished: 1}}, progress)
+}
+
+func TestWaitForJobFailed(t *testing.T) {
+	setupJobSequenceResponder(t, "123", []Job{
+		{Name: "123", State: JobStateRunning, NodeStates: NodeStates{Running: 1}},
+		{Name: "123", State: JobStateFailed, NodeStates: NodeStates{Failed: 1}},
+	})
+
+	job, nodes, err := orchClient.WaitForJob(context.Background(), "123", testPollPolicy, nil)
+	var failedErr *JobFailedError
+	require.True(t, errors.As(err, &failedErr))
+	require.Equal(t, job, failedErr.Job)
+	require.Equal(t, nodes, failedErr.Nodes)
+	require.Equal(t, "job 123 failed: 1 node(s) failed, 0 node(s) errored", err.Error())
+
+	// Stopped jobs are terminal but not successful
+	setupJobSequenceResponder(t, "123", []Job{{Name: "123", State: JobStateStopped}})
+	_, _, err = orchClient.WaitForJob(context.Background(), "123", testPollPolicy, nil)
+	require.True(t, errors.As(err, &failedErr))
+	require.Equal(t, JobStateStopped, failedErr.Job.State)
+}
+
+func TestWaitForJobCancelled(t *testing.T) {
+	setupJobSequenceResponder(t, "123", []Job{{Name: "123", State: JobStateRunning}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	job, nodes, err := orchClient.WaitForJob(ctx, "123", testPollPolicy, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Nil(t, job)
+	require.Nil(t, nodes)
+}
+
+func TestWaitForJobError(t *testing.T) {
+	setupErrorResponder(t, strings.ReplaceAll(orchJob, "{job-id}", "123"))
+	job, nodes, err := orchClient.WaitForJob(context.Background(), "123", testPollPolicy, nil)
+	require.Nil(t, job)
+	require.Nil(t, nodes)
+	require.Equal(t, expectedErrorAt(strings.ReplaceAll(orchJob, "{job-id}", "123")), err)
+}
+
+func TestPollPolicyNext(t *testing.T) {
+	policy := PollPolicy{Interval: time.Second, MaxInterval: 3 * time.Second, Multiplier: 2}
+	require.Equal(t, 2*time.Second, policy.next(time.Second))
+	require.Equal(t, 3*time.Second, policy.next(2*time.Second))
+
+	constant := PollPolicy{Interval: time.Second}
+	require.Equal(t, time.Second, constant.next(time.Second))
+}
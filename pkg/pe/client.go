@@ -4,24 +4,30 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"net/http"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/puppetlabs/go-pe-client/pkg/tokenauth"
 )
 
 // Client for the PE API
 type Client struct {
-	resty  *resty.Client
-	strict bool
+	resty       *resty.Client
+	strict      bool
+	tokenSource tokenauth.TokenSource
 }
 
-// NewClient access the PE API via TLS
+// NewClient access the PE API via TLS, an empty token leaves out the X-Authentication header
 func NewClient(hostURL string, token string, tlsConfig *tls.Config) *Client {
 	r := resty.New()
 	if tlsConfig != nil {
 		r.SetTLSClientConfig(tlsConfig)
 	}
 	r.SetBaseURL(hostURL)
-	r.SetHeader("X-Authentication", token)
+	if token != "" {
+		r.SetHeader("X-Authentication", token)
+	}
 	r.SetRedirectPolicy(resty.NoRedirectPolicy())
 
 	client := Client{resty: r}
@@ -34,3 +40,25 @@ func NewClient(hostURL string, token string, tlsConfig *tls.Config) *Client {
 	}
 	return &client
 }
+
+// SetTransport lets the caller overwrite the default transport used by the client.
+// This is useful when injecting mock transports for testing purposes.
+func (c *Client) SetTransport(tripper http.RoundTripper) {
+	if c.tokenSource != nil {
+		tripper = tokenauth.NewTransport(tripper, c.tokenSource)
+	}
+	c.resty.SetTransport(tripper)
+}
+
+// SetRetryPolicy sets how the client retries requests that fail with a transient error.
+// The client does not retry unless a policy is set, e.g. retry.DefaultPolicy().
+func (c *Client) SetRetryPolicy(policy retry.Policy) {
+	policy.Apply(c.resty)
+}
+
+// SetTokenSource makes the client take the X-Authentication token of each request from source instead of
+// the token given to NewClient. A request rejected with a 401 is sent once more with a refreshed token.
+func (c *Client) SetTokenSource(source tokenauth.TokenSource) {
+	c.tokenSource = source
+	c.resty.SetTransport(tokenauth.NewTransport(c.resty.GetClient().Transport, source))
+}
@@ -0,0 +1,30 @@
+package pe
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRetryPolicy(t *testing.T) {
+	peClient.SetRetryPolicy(retry.Policy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: time.Millisecond})
+	defer peClient.SetRetryPolicy(retry.Policy{})
+
+	// Test a 503 is retried
+	calls := setupUnavailableResponder(t, apiEnvironments, "environments.json")
+	actual, err := peClient.Environments()
+	require.Nil(t, err)
+	require.Equal(t, expectedEnvironments, actual)
+	require.Equal(t, 2, calls())
+
+	// Test a 403 is not retried
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodGet, peHostURL+apiEnvironments, httpmock.NewStringResponder(http.StatusForbidden, "Forbidden"))
+	_, err = peClient.Environments()
+	require.Error(t, err)
+	require.Equal(t, 1, httpmock.GetTotalCallCount())
+}
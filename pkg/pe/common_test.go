@@ -30,6 +30,36 @@ func setupGetResponder(t *testing.T, url, query, responseFilename string) {
 	response.Body.Close()
 }
 
+// setupUnavailableResponder responds to GET requests for url with a 503 the first time and the given
+// response file after that. It returns a func counting the calls made.
+func setupUnavailableResponder(t *testing.T, url, responseFilename string) func() int {
+	httpmock.Reset()
+	responseBody, err := os.ReadFile("testdata/apidocs/" + responseFilename)
+	require.Nil(t, err)
+
+	calls := 0
+	httpmock.RegisterResponder(http.MethodGet, peHostURL+url, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, "Service Unavailable"), nil
+		}
+		response := httpmock.NewBytesResponse(http.StatusOK, responseBody)
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+	return func() int { return calls }
+}
+
+// setupBlockingResponder responds to every request for url only once the request's context is done
+func setupBlockingResponder(url string) {
+	httpmock.Reset()
+	responder := func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	httpmock.RegisterResponder(http.MethodGet, peHostURL+url, responder)
+}
+
 var peClient *Client
 
 var peHostURL = "https://test-host"
@@ -1,7 +1,10 @@
 package pe
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 )
 
 const (
@@ -10,8 +13,14 @@ const (
 
 // Environments gets the list of environments from the PE API (GET /api/environments)
 func (c *Client) Environments() ([]string, error) {
+	return c.EnvironmentsCtx(context.Background())
+}
+
+// EnvironmentsCtx is Environments with a context that cancels the request when done
+func (c *Client) EnvironmentsCtx(ctx context.Context) ([]string, error) {
 	payload := []string{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		Get(apiEnvironments)
 	if err != nil {
@@ -21,7 +30,7 @@ func (c *Client) Environments() ([]string, error) {
 		if r.Error() != nil {
 			return nil, r.Error().(error)
 		}
-		return nil, fmt.Errorf("%s error: %s", apiEnvironments, r.Status())
+		return nil, fmt.Errorf("%s error: %s: %w", apiEnvironments, r.Status(), apierror.FromResponse(apierror.ServiceConsole, r))
 	}
 	return payload, nil
 }
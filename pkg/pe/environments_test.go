@@ -1,8 +1,12 @@
 package pe
 
 import (
+	"context"
+	"net/http"
 	"testing"
 
+	"github.com/jarcoal/httpmock"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -12,6 +16,28 @@ func TestEnvironments(t *testing.T) {
 	actual, err := peClient.Environments()
 	require.Nil(t, err)
 	require.Equal(t, expectedEnvironments, actual)
+
+	// Test error
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodGet, peHostURL+apiEnvironments, httpmock.NewStringResponder(http.StatusForbidden, "Forbidden"))
+	actual, err = peClient.Environments()
+	require.Nil(t, actual)
+	require.EqualError(t, err, "/api/environments error: 403: Forbidden")
+	require.True(t, apierror.IsForbidden(err))
+}
+
+func TestEnvironmentsCtx(t *testing.T) {
+	setupGetResponder(t, apiEnvironments, "", "environments.json")
+	actual, err := peClient.EnvironmentsCtx(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, expectedEnvironments, actual)
+
+	setupBlockingResponder(apiEnvironments)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actual, err = peClient.EnvironmentsCtx(ctx)
+	require.Nil(t, actual)
+	require.ErrorIs(t, err, context.Canceled)
 }
 
 var expectedEnvironments = []string{"production", "test"}
@@ -0,0 +1,256 @@
+package peclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Locations of the Puppet client tools configuration, the user's files take precedence over the global ones
+const (
+	GlobalClientToolsDir = "/etc/puppetlabs/client-tools"
+	UserClientToolsDir   = "~/.puppetlabs/client-tools"
+	DefaultTokenFile     = "~/.puppetlabs/token"
+)
+
+// ClientTools holds the connection settings used by the Puppet client tools, such as puppet task and
+// puppet query, read from orchestrator.conf, puppetdb.conf and puppet-code.conf. Paths have ~ expanded.
+// OrchestratorURL	The service-url of orchestrator.conf.
+// Environment	The default environment of orchestrator.conf.
+// PuppetDBURLs	The server_urls of puppetdb.conf.
+// CodeManagerURL	The service-url of puppet-code.conf.
+// CACert	The CA certificate to trust, from the first of orchestrator.conf, puppetdb.conf and puppet-code.conf to set one.
+// Cert, Key	The client certificate and key of puppetdb.conf.
+// TokenFile	The token-file to authenticate with, DefaultTokenFile if no file sets one.
+type ClientTools struct {
+	OrchestratorURL string
+	Environment     string
+	PuppetDBURLs    []string
+	CodeManagerURL  string
+	CACert          string
+	Cert            string
+	Key             string
+	TokenFile       string
+}
+
+// orchestratorConf is the format of orchestrator.conf
+type orchestratorConf struct {
+	Options struct {
+		ServiceURL  string `json:"service-url"`
+		CACert      string `json:"cacert"`
+		TokenFile   string `json:"token-file"`
+		Environment string `json:"environment"`
+	} `json:"options"`
+}
+
+// puppetDBConf is the format of puppetdb.conf
+type puppetDBConf struct {
+	PuppetDB struct {
+		ServerURLs urlList `json:"server_urls"`
+		CACert     string  `json:"cacert"`
+		Cert       string  `json:"cert"`
+		Key        string  `json:"key"`
+		TokenFile  string  `json:"token-file"`
+	} `json:"puppetdb"`
+}
+
+// puppetCodeConf is the format of puppet-code.conf
+type puppetCodeConf struct {
+	ServiceURL string `json:"service-url"`
+	CACert     string `json:"cacert"`
+	TokenFile  string `json:"token-file"`
+}
+
+// urlList accepts server_urls as either an array or a comma separated string
+type urlList []string
+
+func (u *urlList) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*u = list
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("server_urls must be a string or an array of strings")
+	}
+	*u = nil
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			*u = append(*u, part)
+		}
+	}
+	return nil
+}
+
+// LoadClientTools reads the client tools configuration from GlobalClientToolsDir and UserClientToolsDir
+func LoadClientTools() (*ClientTools, error) {
+	return LoadClientToolsFrom(GlobalClientToolsDir, UserClientToolsDir)
+}
+
+// LoadClientToolsFrom reads the client tools configuration from the given directories, each setting in a
+// later directory overriding the same setting in an earlier one. Missing files are skipped.
+func LoadClientToolsFrom(dirs ...string) (*ClientTools, error) {
+	var (
+		orchestrator orchestratorConf
+		puppetDB     puppetDBConf
+		puppetCode   puppetCodeConf
+	)
+	for _, dir := range dirs {
+		for name, conf := range map[string]interface{}{
+			"orchestrator.conf": &orchestrator,
+			"puppetdb.conf":     &puppetDB,
+			"puppet-code.conf":  &puppetCode,
+		} {
+			if err := readConf(filepath.Join(dir, name), conf); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	ct := &ClientTools{
+		OrchestratorURL: orchestrator.Options.ServiceURL,
+		Environment:     orchestrator.Options.Environment,
+		PuppetDBURLs:    puppetDB.PuppetDB.ServerURLs,
+		CodeManagerURL:  puppetCode.ServiceURL,
+		CACert:          firstNonEmpty(orchestrator.Options.CACert, puppetDB.PuppetDB.CACert, puppetCode.CACert),
+		Cert:            puppetDB.PuppetDB.Cert,
+		Key:             puppetDB.PuppetDB.Key,
+		TokenFile:       firstNonEmpty(orchestrator.Options.TokenFile, puppetDB.PuppetDB.TokenFile, puppetCode.TokenFile, DefaultTokenFile),
+	}
+
+	var err error
+	for _, path := range []*string{&ct.CACert, &ct.Cert, &ct.Key, &ct.TokenFile} {
+		if *path, err = expandHome(*path); err != nil {
+			return nil, err
+		}
+	}
+	return ct, nil
+}
+
+// readConf decodes the JSON file at path into conf, doing nothing if the file does not exist
+func readConf(path string, conf interface{}) error {
+	path, err := expandHome(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, conf); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// expandHome replaces a leading ~ in path with the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Token reads the token from TokenFile
+func (ct *ClientTools) Token() (string, error) {
+	data, err := os.ReadFile(filepath.Clean(ct.TokenFile))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Options returns the console hostname, taken from the orchestrator or else the PuppetDB URL, and
+// options with the orchestrator and PuppetDB endpoints set from their URLs. Only the first PuppetDB URL is used.
+// The client certificate and key are set in the options but the auth mode is left to the caller.
+func (ct *ClientTools) Options() (string, *Options, error) {
+	options := &Options{CertFile: ct.Cert, KeyFile: ct.Key}
+	var hostname string
+	if ct.OrchestratorURL != "" {
+		endpoint, err := parseEndpoint(ct.OrchestratorURL)
+		if err != nil {
+			return "", nil, err
+		}
+		options.Orchestrator = endpoint
+		hostname = endpoint.Hostname
+	}
+	if len(ct.PuppetDBURLs) > 0 {
+		endpoint, err := parseEndpoint(ct.PuppetDBURLs[0])
+		if err != nil {
+			return "", nil, err
+		}
+		options.PuppetDB = endpoint
+		if hostname == "" {
+			hostname = endpoint.Hostname
+		}
+	}
+	if hostname == "" {
+		return "", nil, errors.New("peclient: the client tools configuration has no orchestrator or puppetdb URL")
+	}
+	return hostname, options, nil
+}
+
+// New creates the clients for the PE installation in the configuration, trusting CACert and
+// authenticating with the token in TokenFile. If there is no token file but a client certificate
+// and key are configured the clients authenticate with the certificate instead.
+func (ct *ClientTools) New() (*PE, error) {
+	hostname, options, err := ct.Options()
+	if err != nil {
+		return nil, err
+	}
+	token, err := ct.Token()
+	if errors.Is(err, os.ErrNotExist) && ct.Cert != "" && ct.Key != "" {
+		options.AuthMode = AuthCert
+	} else if err != nil {
+		return nil, err
+	}
+	var caCert []byte
+	if ct.CACert != "" {
+		if caCert, err = os.ReadFile(filepath.Clean(ct.CACert)); err != nil {
+			return nil, err
+		}
+	}
+	return New(hostname, caCert, token, options)
+}
+
+// parseEndpoint splits a service URL such as https://pe.example.com:8143/prefix into an Endpoint
+func parseEndpoint(serviceURL string) (Endpoint, error) {
+	u, err := url.Parse(serviceURL)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	if u.Scheme != "https" || u.Hostname() == "" {
+		return Endpoint{}, fmt.Errorf("peclient: service URL %q must be an https URL", serviceURL)
+	}
+	endpoint := Endpoint{Hostname: u.Hostname(), Prefix: strings.TrimSuffix(u.Path, "/")}
+	if u.Port() != "" {
+		if endpoint.Port, err = strconv.Atoi(u.Port()); err != nil {
+			return Endpoint{}, err
+		}
+	} else {
+		endpoint.Port = 443
+	}
+	return endpoint, nil
+}
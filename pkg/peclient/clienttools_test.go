@@ -0,0 +1,90 @@
+package peclient
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadClientToolsFrom(t *testing.T) {
+	home, err := os.UserHomeDir()
+	require.Nil(t, err)
+
+	ct, err := LoadClientToolsFrom("testdata/client-tools/global", "testdata/client-tools/user")
+	require.Nil(t, err)
+	require.Equal(t, &ClientTools{
+		OrchestratorURL: "https://pe.example.com:8143",
+		Environment:     "production",
+		PuppetDBURLs:    []string{"https://puppetdb.example.com:8081", "https://replica.example.com:8081"},
+		CodeManagerURL:  "https://pe.example.com:8170/code-manager",
+		CACert:          "testdata/ca.pem",
+		Cert:            filepath.Join(home, ".puppetlabs/ssl/certs/agent.pem"),
+		Key:             filepath.Join(home, ".puppetlabs/ssl/private_keys/agent.pem"),
+		TokenFile:       "testdata/token",
+	}, ct)
+
+	token, err := ct.Token()
+	require.Nil(t, err)
+	require.Equal(t, "token-from-file", token)
+
+	// Test defaults without any files
+	ct, err = LoadClientToolsFrom("testdata/client-tools/missing")
+	require.Nil(t, err)
+	require.Equal(t, &ClientTools{TokenFile: filepath.Join(home, ".puppetlabs/token")}, ct)
+
+	// Test invalid file
+	_, err = LoadClientToolsFrom("testdata/client-tools/invalid")
+	require.EqualError(t, err, "testdata/client-tools/invalid/puppetdb.conf: server_urls must be a string or an array of strings")
+}
+
+func TestClientToolsOptions(t *testing.T) {
+	ct := &ClientTools{
+		OrchestratorURL: "https://pe.example.com:8143",
+		PuppetDBURLs:    []string{"https://puppetdb.example.com/pdb-proxy/"},
+	}
+	hostname, options, err := ct.Options()
+	require.Nil(t, err)
+	require.Equal(t, "pe.example.com", hostname)
+	require.Equal(t, Endpoint{Hostname: "pe.example.com", Port: 8143}, options.Orchestrator)
+	require.Equal(t, Endpoint{Hostname: "puppetdb.example.com", Port: 443, Prefix: "/pdb-proxy"}, options.PuppetDB)
+
+	// Test hostname from PuppetDB
+	hostname, _, err = (&ClientTools{PuppetDBURLs: ct.PuppetDBURLs}).Options()
+	require.Nil(t, err)
+	require.Equal(t, "puppetdb.example.com", hostname)
+
+	// Test errors
+	_, _, err = (&ClientTools{}).Options()
+	require.EqualError(t, err, "peclient: the client tools configuration has no orchestrator or puppetdb URL")
+	_, _, err = (&ClientTools{OrchestratorURL: "http://pe.example.com:8143"}).Options()
+	require.EqualError(t, err, `peclient: service URL "http://pe.example.com:8143" must be an https URL`)
+}
+
+func TestClientToolsNew(t *testing.T) {
+	ct, err := LoadClientToolsFrom("testdata/client-tools/global", "testdata/client-tools/user")
+	require.Nil(t, err)
+
+	p, err := ct.New()
+	require.Nil(t, err)
+	require.Equal(t, "https://pe.example.com:8143", p.URL(ServiceOrchestrator))
+	require.Equal(t, "https://puppetdb.example.com:8081", p.URL(ServicePuppetDB))
+	require.Equal(t, "https://pe.example.com:4433", p.URL(ServiceClassifier))
+	require.Equal(t, "token-from-file", p.Token())
+	require.NotNil(t, p.Transport().(*http.Transport).TLSClientConfig.RootCAs)
+
+	// Test missing token file
+	ct.TokenFile = "testdata/missing-token"
+	_, err = ct.New()
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// Test the client certificate is used without a token file
+	ct.Cert = "testdata/agent.pem"
+	ct.Key = "testdata/agent-key.pem"
+	p, err = ct.New()
+	require.Nil(t, err)
+	require.Equal(t, "", p.Token())
+	require.NotNil(t, p.Transport().(*http.Transport).TLSClientConfig.GetClientCertificate)
+}
@@ -0,0 +1,272 @@
+// Package peclient builds the clients for every Puppet Enterprise service from a single console hostname,
+// CA and token.
+package peclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/go-pe-client/pkg/certauth"
+	"github.com/puppetlabs/go-pe-client/pkg/classifier"
+	"github.com/puppetlabs/go-pe-client/pkg/orch"
+	"github.com/puppetlabs/go-pe-client/pkg/pe"
+	"github.com/puppetlabs/go-pe-client/pkg/puppetdb"
+	"github.com/puppetlabs/go-pe-client/pkg/rbac"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/puppetlabs/go-pe-client/pkg/tokenauth"
+)
+
+// Service identifies one of the PE services
+type Service string
+
+// PE services and the ports they listen on by default
+const (
+	ServiceOrchestrator Service = "orchestrator"
+	ServicePuppetDB     Service = "puppetdb"
+	ServiceClassifier   Service = "classifier"
+	ServiceRBAC         Service = "rbac"
+	ServiceConsole      Service = "console"
+
+	DefaultOrchestratorPort = 8143
+	DefaultPuppetDBPort     = 8081
+	DefaultClassifierPort   = 4433
+	DefaultRBACPort         = 4433
+	DefaultConsolePort      = 443
+)
+
+// AuthMode selects how the clients authenticate
+type AuthMode int
+
+const (
+	// AuthToken sends the token given to New in the X-Authentication header of every request
+	AuthToken AuthMode = iota
+	// AuthCert presents the client certificate in Options.CertFile and Options.KeyFile, reloading it when
+	// the files change, and sends no token
+	AuthCert
+)
+
+// ErrInvalidCA is returned by New when the CA certificate has no PEM encoded certificates
+var ErrInvalidCA = errors.New("peclient: no certificates found in the CA certificate")
+
+// Endpoint overrides where a service is found, zero values keep the default
+// Hostname	The host running the service, e.g. a PuppetDB on its own host. The default is the console hostname.
+// Port	The port the service listens on. The default is the service's default port.
+// Prefix	A path prepended to every request, e.g. when the service is behind a proxy.
+type Endpoint struct {
+	Hostname string
+	Port     int
+	Prefix   string
+}
+
+// Options customises the clients built by New, nil options use the defaults
+// TLSConfig	The TLS configuration to start from, the CA certificate given to New is added as its RootCAs.
+// Transport	The HTTP transport shared by all of the clients, which brings its own TLS configuration and so cannot be
+// used with TLSConfig or a CA certificate. The default is a transport built from TLSConfig.
+// AuthMode	How the clients authenticate, the default is AuthToken.
+// CertFile, KeyFile	The PEM encoded client certificate and key used with AuthCert.
+// PuppetDBTimeout	The timeout of PuppetDB requests, 0 means no timeout.
+// RetryPolicy	The retry policy of all of the clients, nil means no retries.
+type Options struct {
+	Orchestrator Endpoint
+	PuppetDB     Endpoint
+	Classifier   Endpoint
+	RBAC         Endpoint
+	Console      Endpoint
+
+	TLSConfig       *tls.Config
+	Transport       http.RoundTripper
+	AuthMode        AuthMode
+	CertFile        string
+	KeyFile         string
+	PuppetDBTimeout time.Duration
+	RetryPolicy     *retry.Policy
+}
+
+// PE holds a client for each PE service, all sharing one HTTP transport and connection pool
+type PE struct {
+	token     string
+	urls      map[Service]string
+	transport http.RoundTripper
+
+	orch       *orch.Client
+	puppetDB   *puppetdb.Client
+	classifier *classifier.Client
+	rbac       *rbac.Client
+	console    *pe.Client
+}
+
+// New creates the clients for the PE installation whose console runs on hostname. caCert is the PEM
+// encoded CA certificate of the installation, usually /etc/puppetlabs/puppet/ssl/certs/ca.pem, which
+// may be nil to use the system roots or the RootCAs of options.TLSConfig, and must be nil with a custom
+// options.Transport. token is ignored with AuthCert.
+func New(hostname string, caCert []byte, token string, options *Options) (*PE, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	if hostname == "" || strings.Contains(hostname, "/") {
+		return nil, fmt.Errorf("peclient: invalid hostname %q", hostname)
+	}
+	// A bare IPv6 address does not split, as its colons are not a port
+	if _, _, err := net.SplitHostPort(hostname); err == nil {
+		return nil, fmt.Errorf("peclient: hostname %q has a port, set the Port of the Endpoint instead", hostname)
+	}
+
+	transport := options.Transport
+	if transport != nil && (options.TLSConfig != nil || len(caCert) > 0) {
+		return nil, errors.New("peclient: a CA certificate or TLSConfig cannot be used with a custom Transport")
+	}
+	if options.AuthMode == AuthCert {
+		if transport != nil {
+			return nil, errors.New("peclient: AuthCert cannot be used with a custom Transport")
+		}
+		token = ""
+	}
+	if transport == nil {
+		tlsConfig, err := newTLSConfig(options.TLSConfig, caCert)
+		if err != nil {
+			return nil, err
+		}
+		if options.AuthMode == AuthCert {
+			reloader, err := certauth.NewReloader(options.CertFile, options.KeyFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = tlsConfig
+		transport = t
+	}
+
+	p := &PE{
+		token:     token,
+		transport: transport,
+		urls: map[Service]string{
+			ServiceOrchestrator: options.Orchestrator.url(hostname, DefaultOrchestratorPort),
+			ServicePuppetDB:     options.PuppetDB.url(hostname, DefaultPuppetDBPort),
+			ServiceClassifier:   options.Classifier.url(hostname, DefaultClassifierPort),
+			ServiceRBAC:         options.RBAC.url(hostname, DefaultRBACPort),
+			ServiceConsole:      options.Console.url(hostname, DefaultConsolePort),
+		},
+	}
+
+	p.orch = orch.NewClient(p.urls[ServiceOrchestrator], token, nil)
+	p.orch.SetTransport(transport)
+	p.puppetDB = puppetdb.NewClient(p.urls[ServicePuppetDB], token, nil, options.PuppetDBTimeout)
+	p.puppetDB.SetTransport(transport)
+	p.classifier = classifier.NewClient(p.urls[ServiceClassifier], token, nil)
+	p.classifier.SetTransport(transport)
+	p.rbac = rbac.NewClient(p.urls[ServiceRBAC], nil)
+	p.rbac.SetTransport(transport)
+	p.console = pe.NewClient(p.urls[ServiceConsole], token, nil)
+	p.console.SetTransport(transport)
+
+	if options.RetryPolicy != nil {
+		p.SetRetryPolicy(*options.RetryPolicy)
+	}
+	return p, nil
+}
+
+// newTLSConfig clones base, or starts from an empty config, and trusts caCert if given
+func newTLSConfig(base *tls.Config, caCert []byte) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if base != nil {
+		tlsConfig = base.Clone()
+	}
+	if len(caCert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, ErrInvalidCA
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// url builds the base URL of the service, leaving out the port if it is the HTTPS default
+func (e Endpoint) url(hostname string, defaultPort int) string {
+	if e.Hostname != "" {
+		hostname = e.Hostname
+	}
+	port := defaultPort
+	if e.Port > 0 {
+		port = e.Port
+	}
+	host := hostname
+	if port != 443 {
+		host = net.JoinHostPort(hostname, strconv.Itoa(port))
+	} else if strings.Contains(hostname, ":") {
+		host = "[" + hostname + "]"
+	}
+
+	prefix := strings.TrimSuffix(e.Prefix, "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return "https://" + host + prefix
+}
+
+// Orch returns the orchestrator client
+func (p *PE) Orch() *orch.Client {
+	return p.orch
+}
+
+// PuppetDB returns the PuppetDB client
+func (p *PE) PuppetDB() *puppetdb.Client {
+	return p.puppetDB
+}
+
+// Classifier returns the node classifier client
+func (p *PE) Classifier() *classifier.Client {
+	return p.classifier
+}
+
+// RBAC returns the RBAC client, its calls take the token from Token
+func (p *PE) RBAC() *rbac.Client {
+	return p.rbac
+}
+
+// Console returns the client for the PE console API
+func (p *PE) Console() *pe.Client {
+	return p.console
+}
+
+// Token returns the token the clients authenticate with, empty with AuthCert
+func (p *PE) Token() string {
+	return p.token
+}
+
+// URL returns the base URL used for the given service, or an empty string for an unknown service
+func (p *PE) URL(service Service) string {
+	return p.urls[service]
+}
+
+// Transport returns the HTTP transport shared by the clients
+func (p *PE) Transport() http.RoundTripper {
+	return p.transport
+}
+
+// SetRetryPolicy sets the retry policy of all of the clients
+func (p *PE) SetRetryPolicy(policy retry.Policy) {
+	p.orch.SetRetryPolicy(policy)
+	p.puppetDB.SetRetryPolicy(policy)
+	p.classifier.SetRetryPolicy(policy)
+	p.rbac.SetRetryPolicy(policy)
+	p.console.SetRetryPolicy(policy)
+}
+
+// SetTokenSource makes the orchestrator, PuppetDB, classifier and console clients take their token from
+// source, e.g. a tokenauth.RBACTokenSource built on the RBAC client
+func (p *PE) SetTokenSource(source tokenauth.TokenSource) {
+	p.orch.SetTokenSource(source)
+	p.puppetDB.SetTokenSource(source)
+	p.classifier.SetTokenSource(source)
+	p.console.SetTokenSource(source)
+}
@@ -0,0 +1,155 @@
+package peclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	caCert, err := os.ReadFile("testdata/ca.pem")
+	require.Nil(t, err)
+
+	p, err := New("pe.example.com", caCert, "xxxx", nil)
+	require.Nil(t, err)
+	require.Equal(t, "https://pe.example.com:8143", p.URL(ServiceOrchestrator))
+	require.Equal(t, "https://pe.example.com:8081", p.URL(ServicePuppetDB))
+	require.Equal(t, "https://pe.example.com:4433", p.URL(ServiceClassifier))
+	require.Equal(t, "https://pe.example.com:4433", p.URL(ServiceRBAC))
+	require.Equal(t, "https://pe.example.com", p.URL(ServiceConsole))
+	require.Equal(t, "", p.URL("unknown"))
+	require.Equal(t, "xxxx", p.Token())
+	require.NotNil(t, p.Orch())
+	require.NotNil(t, p.PuppetDB())
+	require.NotNil(t, p.Classifier())
+	require.NotNil(t, p.RBAC())
+	require.NotNil(t, p.Console())
+
+	transport, ok := p.Transport().(*http.Transport)
+	require.True(t, ok)
+	require.NotNil(t, transport.TLSClientConfig.RootCAs)
+	require.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+
+	// Test the base TLS config is kept
+	p, err = New("pe.example.com", nil, "xxxx", &Options{TLSConfig: &tls.Config{ServerName: "puppet", MinVersion: tls.VersionTLS13}})
+	require.Nil(t, err)
+	transport = p.Transport().(*http.Transport)
+	require.Equal(t, "puppet", transport.TLSClientConfig.ServerName)
+	require.Nil(t, transport.TLSClientConfig.RootCAs)
+
+	// Test invalid CA
+	_, err = New("pe.example.com", []byte("not a certificate"), "xxxx", nil)
+	require.ErrorIs(t, err, ErrInvalidCA)
+
+	// Test invalid hostname
+	_, err = New("https://pe.example.com", nil, "xxxx", nil)
+	require.EqualError(t, err, `peclient: invalid hostname "https://pe.example.com"`)
+	_, err = New("", nil, "xxxx", nil)
+	require.NotNil(t, err)
+	_, err = New("pe.example.com:8143", nil, "xxxx", nil)
+	require.EqualError(t, err, `peclient: hostname "pe.example.com:8143" has a port, set the Port of the Endpoint instead`)
+	_, err = New("[::1]:8143", nil, "xxxx", nil)
+	require.NotNil(t, err)
+
+	// Test a bare IPv6 address is a hostname
+	p, err = New("::1", nil, "xxxx", nil)
+	require.Nil(t, err)
+	require.Equal(t, "https://[::1]:8143", p.URL(ServiceOrchestrator))
+}
+
+func TestNewWithEndpoints(t *testing.T) {
+	p, err := New("pe.example.com", nil, "xxxx", &Options{
+		Orchestrator: Endpoint{Port: 9143},
+		PuppetDB:     Endpoint{Hostname: "puppetdb.example.com", Prefix: "pdb-proxy/"},
+		Classifier:   Endpoint{Prefix: "/classifier"},
+		RBAC:         Endpoint{Hostname: "::1"},
+		Console:      Endpoint{Hostname: "::1", Port: 443},
+	})
+	require.Nil(t, err)
+	require.Equal(t, "https://pe.example.com:9143", p.URL(ServiceOrchestrator))
+	require.Equal(t, "https://puppetdb.example.com:8081/pdb-proxy", p.URL(ServicePuppetDB))
+	require.Equal(t, "https://pe.example.com:4433/classifier", p.URL(ServiceClassifier))
+	require.Equal(t, "https://[::1]:4433", p.URL(ServiceRBAC))
+	require.Equal(t, "https://[::1]", p.URL(ServiceConsole))
+}
+
+func TestSharedTransport(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	p, err := New("pe.example.com", nil, "xxxx", &Options{
+		Transport:   transport,
+		PuppetDB:    Endpoint{Prefix: "/proxy"},
+		RetryPolicy: &retry.Policy{MaxRetries: 1, WaitTime: time.Millisecond, MaxWaitTime: time.Millisecond},
+	})
+	require.Nil(t, err)
+	require.Equal(t, transport, p.Transport())
+
+	// Test TLS settings are refused with a custom transport, which would ignore them
+	_, err = New("pe.example.com", []byte("ca"), "xxxx", &Options{Transport: transport})
+	require.EqualError(t, err, "peclient: a CA certificate or TLSConfig cannot be used with a custom Transport")
+	_, err = New("pe.example.com", nil, "xxxx", &Options{Transport: transport, TLSConfig: &tls.Config{}})
+	require.EqualError(t, err, "peclient: a CA certificate or TLSConfig cannot be used with a custom Transport")
+
+	transport.RegisterResponder(http.MethodGet, "https://pe.example.com:8143/orchestrator/v1/inventory",
+		httpmock.ResponderFromResponse(jsonResponse(`{"items": [{"name": "foo", "connected": true}]}`)))
+	transport.RegisterResponder(http.MethodGet, "https://pe.example.com:8081/proxy/pdb/query/v4/nodes",
+		func(req *http.Request) (*http.Response, error) {
+			// The first request is unavailable to check the retry policy reaches PuppetDB
+			if transport.GetCallCountInfo()["GET https://pe.example.com:8081/proxy/pdb/query/v4/nodes"] == 1 {
+				return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+			}
+			return jsonResponse(`[{"certname": "foo"}]`), nil
+		})
+	transport.RegisterResponder(http.MethodGet, "https://pe.example.com/api/environments",
+		httpmock.ResponderFromResponse(jsonResponse(`["production"]`)))
+
+	inventory, err := p.Orch().Inventory()
+	require.Nil(t, err)
+	require.Equal(t, "foo", inventory[0].Name)
+
+	nodes, err := p.PuppetDB().Nodes("", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, "foo", nodes[0].Certname)
+
+	environments, err := p.Console().Environments()
+	require.Nil(t, err)
+	require.Equal(t, []string{"production"}, environments)
+
+	info := transport.GetCallCountInfo()
+	require.Equal(t, 1, info["GET https://pe.example.com:8143/orchestrator/v1/inventory"])
+	require.Equal(t, 2, info["GET https://pe.example.com:8081/proxy/pdb/query/v4/nodes"])
+	require.Equal(t, 1, info["GET https://pe.example.com/api/environments"])
+}
+
+func jsonResponse(body string) *http.Response {
+	response := httpmock.NewStringResponse(http.StatusOK, body)
+	response.Header.Set("Content-Type", "application/json")
+	return response
+}
+
+func TestNewWithCertificate(t *testing.T) {
+	p, err := New("pe.example.com", nil, "xxxx", &Options{
+		AuthMode: AuthCert,
+		CertFile: "testdata/agent.pem",
+		KeyFile:  "testdata/agent-key.pem",
+	})
+	require.Nil(t, err)
+	require.Equal(t, "", p.Token())
+	tlsConfig := p.Transport().(*http.Transport).TLSClientConfig
+	cert, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.Nil(t, err)
+	require.NotEmpty(t, cert.Certificate)
+
+	// Test missing certificate
+	_, err = New("pe.example.com", nil, "", &Options{AuthMode: AuthCert, CertFile: "testdata/missing.pem", KeyFile: "testdata/agent-key.pem"})
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// Test custom transport
+	_, err = New("pe.example.com", nil, "", &Options{AuthMode: AuthCert, Transport: httpmock.NewMockTransport()})
+	require.EqualError(t, err, "peclient: AuthCert cannot be used with a custom Transport")
+}
@@ -0,0 +1,117 @@
+package puppetdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+)
+
+const (
+	catalogs = "/pdb/query/v4/catalogs"
+	catalog  = "/pdb/query/v4/catalogs/{certname}"
+)
+
+// Catalogs will return the most recent catalog of each node matching the given query
+func (c *Client) Catalogs(query string, pagination *Pagination, orderBy *OrderBy) ([]Catalog, error) {
+	return c.CatalogsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// CatalogsCtx is Catalogs with a context that cancels the request when done
+func (c *Client) CatalogsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Catalog, error) {
+	payload := []Catalog{}
+	err := getRequest(ctx, c, catalogs, query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// PaginatedCatalogs works just like Catalogs, but returns a CatalogsCursor that
+// provides methods for iterating over N pages of catalogs. If pagination is
+// nil, then a default configuration with a limit of 100 is used instead.
+func (c *Client) PaginatedCatalogs(query string, pagination *Pagination, orderBy *OrderBy) (*CatalogsCursor, error) {
+	return c.PaginatedCatalogsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedCatalogsCtx is PaginatedCatalogs with a context that cancels the request for the first page of catalogs,
+// use NextCtx to fetch the other pages with a context
+func (c *Client) PaginatedCatalogsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*CatalogsCursor, error) {
+	cursor, err := newPageCursor[Catalog](ctx, c, catalogs, query, pagination, orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
+	}
+
+	return &CatalogsCursor{Cursor: cursor}, nil
+}
+
+// Catalog will return the most recent catalog of a node by certname
+func (c *Client) Catalog(certname string) (*Catalog, error) {
+	return c.CatalogCtx(context.Background(), certname)
+}
+
+// CatalogCtx is Catalog with a context that cancels the request when done
+func (c *Client) CatalogCtx(ctx context.Context, certname string) (*Catalog, error) {
+	payload := &Catalog{}
+	r, err := c.resty.R().
+		SetContext(ctx).
+		SetResult(&payload).
+		SetPathParams(map[string]string{"certname": certname}).
+		Get(catalog)
+	if err != nil {
+		return nil, err
+	}
+	if r.IsError() {
+		if r.Error() != nil {
+			return nil, r.Error().(error)
+		}
+		return nil, fmt.Errorf("%s error: %s: %w", strings.ReplaceAll(catalog, "{certname}", certname), r.Status(),
+			apierror.FromResponse(apierror.ServicePuppetDB, r))
+	}
+	return payload, nil
+}
+
+// Catalog is the catalog a node was last compiled, with its resources and the edges between them
+type Catalog struct {
+	Certname          string           `json:"certname"`
+	Version           string           `json:"version"`
+	TransactionUUID   string           `json:"transaction_uuid"`
+	CatalogUUID       string           `json:"catalog_uuid"`
+	CodeID            string           `json:"code_id"`
+	JobID             string           `json:"job_id"`
+	ProducerTimestamp time.Time        `json:"producer_timestamp"`
+	Producer          string           `json:"producer"`
+	Hash              string           `json:"hash"`
+	Environment       string           `json:"environment"`
+	Edges             CatalogEdges     `json:"edges"`
+	Resources         CatalogResources `json:"resources"`
+	Count             int              `json:"count"`
+}
+
+// CatalogEdges are the edges of a catalog, Href is where they can be queried from
+type CatalogEdges struct {
+	Href string `json:"href"`
+	Data []Edge `json:"data"`
+}
+
+// CatalogResources are the resources of a catalog, Href is where they can be queried from
+type CatalogResources struct {
+	Href string     `json:"href"`
+	Data []Resource `json:"data"`
+}
+
+// Edge is a relationship between two resources of a catalog.
+// Relationship is one of contains, before, required-by, notifies or subscription-of.
+type Edge struct {
+	Certname     string `json:"certname,omitempty"`
+	SourceType   string `json:"source_type"`
+	SourceTitle  string `json:"source_title"`
+	TargetType   string `json:"target_type"`
+	TargetTitle  string `json:"target_title"`
+	Relationship string `json:"relationship"`
+}
+
+// CatalogsCursor is a pagination cursor that provides convenience methods for
+// stepping through pages of catalogs.
+type CatalogsCursor struct {
+	*Cursor[Catalog]
+}
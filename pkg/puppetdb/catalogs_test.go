@@ -0,0 +1,94 @@
+package puppetdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogs(t *testing.T) {
+	query := `["=", "certname", "lenient-veranda.delivery.puppetlabs.net"]`
+	setupGetResponder(t, catalogs, "query="+query, "catalogs-response.json")
+	actual, err := pdbClient.Catalogs(query, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, []Catalog{expectedCatalog}, actual)
+}
+
+func TestPaginatedCatalogs(t *testing.T) {
+	pagination := Pagination{Limit: 1, IncludeTotal: true}
+	setupPaginatedGetResponder(t, catalogs, "", mockPaginatedGetOptions{
+		limit: pagination.Limit,
+		total: 2,
+		pageFilenames: []string{
+			"catalogs-response.json",
+			"catalogs-response.json",
+		},
+	})
+
+	cursor, err := pdbClient.PaginatedCatalogs("", &pagination, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, cursor.TotalPages())
+
+	actual, err := cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, []Catalog{expectedCatalog}, actual)
+
+	actual, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+	require.Len(t, actual, 1)
+}
+
+func TestCatalog(t *testing.T) {
+	catalogURL := strings.ReplaceAll(catalog, "{certname}", "lenient-veranda.delivery.puppetlabs.net")
+
+	// Test success
+	setupGetResponder(t, catalogURL, "", "catalog-response.json")
+	actual, err := pdbClient.Catalog("lenient-veranda.delivery.puppetlabs.net")
+	require.Nil(t, err)
+	require.Equal(t, &expectedCatalog, actual)
+
+	// Test a node without a catalog
+	setupResponderWithStatusCodeAndBody(t, catalogURL, http.StatusNotFound, map[string]string{"error": "No information is known about lenient-veranda.delivery.puppetlabs.net"})
+	_, err = pdbClient.Catalog("lenient-veranda.delivery.puppetlabs.net")
+	require.True(t, apierror.IsNotFound(err))
+
+	// Test deadline reaches the request
+	setupBlockingResponder(catalogURL)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pdbClient.CatalogCtx(ctx, "lenient-veranda.delivery.puppetlabs.net")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+var expectedCatalog = Catalog{
+	Certname:          "lenient-veranda.delivery.puppetlabs.net",
+	Version:           "1584699453",
+	TransactionUUID:   "e7a1a5ba-a6d5-4a32-9e9d-5bbb7e0e1f4c",
+	CatalogUUID:       "1f0b1f5e-2a1b-4e4f-8b29-7e7f0e2f2c4a",
+	ProducerTimestamp: time.Date(2020, 3, 20, 10, 17, 33, 991000000, time.UTC),
+	Producer:          "pe-master.delivery.puppetlabs.net",
+	Hash:              "3f6f6b1d1a34d1dcd1e2b4c6c8bdb56b4d1f2e3a",
+	Environment:       "production",
+	Edges: CatalogEdges{
+		Href: "/pdb/query/v4/catalogs/lenient-veranda.delivery.puppetlabs.net/edges",
+		Data: []Edge{{SourceType: "Class", SourceTitle: "Ntp", TargetType: "File", TargetTitle: "/etc/ntp.conf", Relationship: "contains"}},
+	},
+	Resources: CatalogResources{
+		Href: "/pdb/query/v4/catalogs/lenient-veranda.delivery.puppetlabs.net/resources",
+		Data: []Resource{{
+			Resource:   "0a4a1c58b2d6c7d1e9f3a4b5c6d7e8f9a0b1c2d3",
+			Type:       "File",
+			Title:      "/etc/ntp.conf",
+			Tags:       []string{"file", "ntp", "class"},
+			File:       "/etc/puppetlabs/code/environments/production/modules/ntp/manifests/config.pp",
+			Line:       12,
+			Parameters: map[string]interface{}{"ensure": "file", "mode": "0644"},
+		}},
+	},
+}
@@ -1,6 +1,7 @@
 package puppetdb
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"fmt"
@@ -10,6 +11,9 @@ import (
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/puppetlabs/go-pe-client/pkg/tokenauth"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,27 +26,40 @@ var (
 	// ErrTransientResponse is returned if the downstream puppetdb api returns
 	// an error that is transient in nature and a retry of the request is
 	// likely to succeed. An example of these could be a gateway timeout error
-	// or some kind of temporary reverse proxy issue.
+	// or some kind of temporary reverse proxy issue. If the client has a
+	// retry policy it is only returned once the retries are used up. It is
+	// returned for the statuses apierror.IsTransient reports as transient.
 	ErrTransientResponse = errors.New("puppetdb: the api response indicates a recoverable error")
 )
 
 // Client for the Orchestrator API
 type Client struct {
-	resty *resty.Client
+	resty       *resty.Client
+	tokenSource tokenauth.TokenSource
 }
 
 // NewClient access the orchestrator API via TLS. N.B. The timeout is the resty http client timeout so is all encompassing
 // and will incorporate connect, TLS handshake, http/s header receipt and general data transfer. The value used for this in
-// ER is 5 seconds which seems reasonable.
+// ER is 5 seconds which seems reasonable. Pass an empty token to authenticate with a client certificate only,
+// see the certauth package.
 func NewClient(hostURL, token string, tlsConfig *tls.Config, timeout time.Duration) *Client {
 	r := resty.New()
 	if tlsConfig != nil {
 		r.SetTLSClientConfig(tlsConfig)
 	}
 	r.SetBaseURL(hostURL)
-	r.SetHeader("X-Authentication", token)
+	if token != "" {
+		r.SetHeader("X-Authentication", token)
+	}
 	r.SetTimeout(timeout)
 	r.SetRedirectPolicy(resty.NoRedirectPolicy())
+	// resty leaves the body of a streamed response to the caller, who only sees the last attempt, so the
+	// body of an attempt that is retried is closed here. Closing a body resty has read already is harmless.
+	r.AddRetryHook(func(resp *resty.Response, _ error) {
+		if resp != nil && resp.RawResponse != nil && resp.Request.Attempt <= r.RetryCount {
+			_ = resp.RawBody().Close()
+		}
+	})
 
 	return &Client{resty: r}
 }
@@ -50,67 +67,100 @@ func NewClient(hostURL, token string, tlsConfig *tls.Config, timeout time.Durati
 // SetTransport lets the caller overwrite the default transport used by the client.
 // This is useful when injecting mock transports for testing purposes.
 func (c *Client) SetTransport(tripper http.RoundTripper) {
+	if c.tokenSource != nil {
+		tripper = tokenauth.NewTransport(tripper, c.tokenSource)
+	}
 	c.resty.SetTransport(tripper)
 }
 
+// SetRetryPolicy sets how the client retries requests that fail with a transient error.
+// The client does not retry unless a policy is set, e.g. retry.DefaultPolicy().
+func (c *Client) SetRetryPolicy(policy retry.Policy) {
+	policy.Apply(c.resty)
+}
+
+// SetTokenSource makes the client take the X-Authentication token of each request from source instead of
+// the token given to NewClient. A request rejected with a 401 is sent once more with a refreshed token.
+func (c *Client) SetTokenSource(source tokenauth.TokenSource) {
+	c.tokenSource = source
+	c.resty.SetTransport(tokenauth.NewTransport(c.resty.GetClient().Transport, source))
+}
+
 // getRequest uses the Given client to make a HTTP GET request to the given path, providing
-// the query.  The result of the request is marshalled into the response type. e.g.
+// the query. The request is cancelled when ctx is done.  The result of the request is marshalled into the response type. e.g.
 // var payload *[]Fact
-// getRequest(client, "/pdb/query/v4/facts",
+// getRequest(ctx, client, "/pdb/query/v4/facts",
 //
 //	query,
 //	&Pagination{Limit: 10, Offset: 20},
 //	&OrderBy{Field: "certname", Order: "asc"},
 //	&payload)
-func getRequest(client *Client, path string, query string, pagination *Pagination, orderBy *OrderBy, response interface{}) error {
-	req := client.resty.R().SetResult(&response)
+func getRequest(ctx context.Context, client *Client, path string, query string, pagination *Pagination, orderBy *OrderBy, response interface{}) error {
+	return getRequestWithParams(ctx, client, path, query, nil, pagination, orderBy, response)
+}
+
+// getRequestWithParams is getRequest with the endpoint specific query parameters params, such as summarize_by
+func getRequestWithParams(ctx context.Context, client *Client, path string, query string, params map[string]string, pagination *Pagination, orderBy *OrderBy, response interface{}) error {
+	req := newQueryRequest(ctx, client, query, params, pagination, orderBy).SetResult(&response)
+
+	r, err := req.Get(path)
+	if err != nil {
+		return requestError(client, path, err)
+	}
+
+	if r.IsError() {
+		return responseError(client, path, r, r.Body())
+	}
+
+	if pagination != nil && pagination.IncludeTotal {
+		pagination.Total = getTotal(r.Header().Get("X-Records"))
+	}
+
+	return nil
+}
+
+// newQueryRequest returns a request with the query, params, pagination and order by set, any of which may be empty
+func newQueryRequest(ctx context.Context, client *Client, query string, params map[string]string, pagination *Pagination, orderBy *OrderBy) *resty.Request {
+	req := client.resty.R().SetContext(ctx)
 	if query != "" {
 		req.SetQueryParam("query", query)
 	}
+	req.SetQueryParams(params)
 	if pagination != nil {
 		req.SetQueryParams(pagination.toParams())
 	}
 	if orderBy != nil {
 		req.SetQueryParams(orderBy.toParams())
 	}
+	return req
+}
 
-	r, err := req.Get(path)
-	if err != nil {
-		var ue *url.Error
-		if errors.As(err, &ue) {
-			return fmt.Errorf("%s%s: %w", client.resty.HostURL, path, ue.Err)
-		}
-
-		return fmt.Errorf("%s%s: %w", client.resty.HostURL, path, err)
+// requestError returns the error for a request to path that got no response
+func requestError(client *Client, path string, err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return fmt.Errorf("%s%s: %w", client.resty.HostURL, path, ue.Err)
 	}
 
-	if r.IsError() {
-		var err error
-
-		switch r.StatusCode() {
-		case http.StatusBadGateway, http.StatusServiceUnavailable,
-			http.StatusGatewayTimeout, http.StatusRequestTimeout,
-			http.StatusUnauthorized, http.StatusPreconditionFailed,
-			http.StatusTooManyRequests:
-
-			err = ErrTransientResponse
-		default:
-			err = ErrNonTransientResponse
-		}
-
-		re := r.Error()
-		if re != nil {
-			err = fmt.Errorf("client error: %v: %w", re, err)
-		}
+	return fmt.Errorf("%s%s: %w", client.resty.HostURL, path, err)
+}
 
-		return fmt.Errorf("%s%s: %s: \"%s\": %w", client.resty.HostURL, path, r.Status(), r.Body(), err)
+// responseError returns the error for an error response r to a request to path, body is the body of the response
+func responseError(client *Client, path string, r *resty.Response, body []byte) error {
+	// The statuses are those the retry package retries, so that apierror.IsTransient agrees
+	err := ErrNonTransientResponse
+	if retry.IsTransientStatus(r.StatusCode()) {
+		err = ErrTransientResponse
 	}
 
-	if pagination != nil && pagination.IncludeTotal {
-		pagination.Total = getTotal(r.Header().Get("X-Records"))
+	re := r.Error()
+	if re != nil {
+		err = fmt.Errorf("client error: %v: %w", re, err)
 	}
+	apiErr := apierror.FromBody(apierror.ServicePuppetDB, r, body)
+	apiErr.Err = err
 
-	return nil
+	return fmt.Errorf("%s%s: %s: %w", client.resty.HostURL, path, r.Status(), apiErr)
 }
 
 // getTotal extracts the total from the X-Records header
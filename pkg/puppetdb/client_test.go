@@ -0,0 +1,69 @@
+package puppetdb
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRetryPolicy(t *testing.T) {
+	pdbClient.SetRetryPolicy(retry.Policy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: time.Millisecond})
+	defer pdbClient.SetRetryPolicy(retry.Policy{})
+
+	responseBody, err := os.ReadFile("testdata/nodes-response.json")
+	require.Nil(t, err)
+	statusCodes := []int{http.StatusServiceUnavailable, http.StatusOK}
+	calls := 0
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodGet, hostURL+nodes, func(req *http.Request) (*http.Response, error) {
+		statusCode := statusCodes[calls%len(statusCodes)]
+		calls++
+		response := httpmock.NewBytesResponse(statusCode, responseBody)
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	})
+
+	// Test transient error is retried
+	actual, err := pdbClient.Nodes("", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedNodes, actual)
+	require.Equal(t, 2, calls)
+
+	// Test transient error once the retries are used up
+	statusCodes = []int{http.StatusServiceUnavailable}
+	calls = 0
+	_, err = pdbClient.Nodes("", nil, nil)
+	require.ErrorIs(t, err, ErrTransientResponse)
+	require.Equal(t, 3, calls)
+}
+
+func TestTransientResponseAgreesWithAPIError(t *testing.T) {
+	for _, statusCode := range []int{http.StatusUnauthorized, http.StatusPreconditionFailed, http.StatusServiceUnavailable} {
+		setupURLResponderWithStatusCode(t, nodes, statusCode)
+		_, err := pdbClient.Nodes("", nil, nil)
+		require.Error(t, err)
+		require.Equal(t, apierror.IsTransient(err), errors.Is(err, ErrTransientResponse), "status %d", statusCode)
+	}
+}
+
+func TestNewClientWithoutToken(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	client := NewClient(hostURL, "", nil, 0)
+	client.SetTransport(transport)
+	transport.RegisterResponder(http.MethodGet, hostURL+nodes, func(req *http.Request) (*http.Response, error) {
+		_, ok := req.Header["X-Authentication"]
+		require.False(t, ok)
+		return httpmock.NewStringResponse(http.StatusOK, `[]`), nil
+	})
+
+	_, err := client.Nodes("", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, 1, transport.GetTotalCallCount())
+}
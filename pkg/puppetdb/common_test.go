@@ -109,6 +109,17 @@ func setupResponderWithStatusCodeAndBody(t *testing.T, url string, statusCode in
 	httpmock.RegisterResponder(http.MethodGet, hostURL+url, responder)
 }
 
+// setupBlockingResponder responds to every request for url only once the request's context is done
+func setupBlockingResponder(url string) {
+	httpmock.Reset()
+	responder := func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	httpmock.RegisterResponder(http.MethodGet, hostURL+url, responder)
+	httpmock.RegisterResponder(http.MethodPost, hostURL+url, responder)
+}
+
 var pdbClient *Client
 
 var hostURL = "https://test-host:8081"
@@ -0,0 +1,277 @@
+package puppetdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// TotalStrategy is how a Cursor learns the total number of results
+type TotalStrategy int
+
+const (
+	// TotalFromFirstPage asks for the total with the first page, so it costs no extra request
+	TotalFromFirstPage TotalStrategy = iota
+	// TotalProbe asks for the total with a request for a single result before the first page
+	TotalProbe
+	// TotalNone never asks for the total, which PuppetDB may have to work to count. The cursor stops
+	// at the first page with fewer results than the limit.
+	TotalNone
+)
+
+// CursorOptions are the options of NewCursor
+type CursorOptions struct {
+	// Prefetch fetches the next page in the background while the caller works through the current one
+	Prefetch bool
+	// Total is how the cursor learns the total number of results, TotalFromFirstPage by default
+	Total TotalStrategy
+	// Params are any other query parameters of the endpoint, such as summarize_by for event-counts
+	Params map[string]string
+}
+
+// Cursor steps through the pages of results of any query endpoint, decoding each result into a T.
+// Unlike the cursors of the Paginated methods it returns io.EOF only once every page has been returned,
+// never together with a page. A Cursor must not be used by more than one goroutine at once.
+type Cursor[T any] struct {
+	client   *Client
+	path     string
+	query    string
+	limit    int
+	offset   int
+	orderBy  *OrderBy
+	options  CursorOptions
+	total    int
+	hasTotal bool
+	started  bool
+	done     bool
+	// pageOffset is the offset of the page last returned
+	pageOffset int
+	// lastPageEOF returns io.EOF together with the last page, as the cursors of the Paginated methods do
+	lastPageEOF bool
+
+	// ctx is the context of prefetches, cancelled by Close
+	ctx           context.Context
+	cancel        context.CancelFunc
+	pending       chan cursorPage[T]
+	cancelPending context.CancelFunc
+}
+
+type cursorPage[T any] struct {
+	items []T
+	total int
+	err   error
+}
+
+// NewCursor returns a cursor over the results of query on endpoint, the path below /pdb/query/v4 such as
+// nodes or package-inventory, or an empty endpoint for the root endpoint and a From query. A path starting
+// with / is used as it is. If pagination is nil a limit of 100 is used, and a limit of 0 fetches every
+// result in one page. options may be nil.
+//
+//	cursor := puppetdb.NewCursor[puppetdb.Node](client, "nodes", query, nil, nil, nil)
+//	defer cursor.Close()
+//	for node, err := range cursor.All(ctx) {
+//		...
+//	}
+func NewCursor[T any](c *Client, endpoint, query string, pagination *Pagination, orderBy *OrderBy, options *CursorOptions) *Cursor[T] {
+	if pagination == nil {
+		pagination = NewDefaultPagination()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cursor := &Cursor[T]{
+		client:     c,
+		path:       endpointPath(endpoint),
+		query:      query,
+		limit:      pagination.Limit,
+		offset:     pagination.Offset,
+		orderBy:    orderBy,
+		pageOffset: pagination.Offset,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	if options != nil {
+		cursor.options = *options
+	}
+	return cursor
+}
+
+// endpointPath returns the path of endpoint, a path below /pdb/query/v4 or a full path starting with /
+func endpointPath(endpoint string) string {
+	if strings.HasPrefix(endpoint, "/") {
+		return endpoint
+	}
+	return strings.TrimSuffix(rootQueryEndpoint+"/"+endpoint, "/")
+}
+
+// Next returns the next page of results. Once every page has been returned it returns nil and io.EOF.
+// After any other error Next may be called again to retry the page.
+func (c *Cursor[T]) Next() ([]T, error) {
+	return c.NextCtx(context.Background())
+}
+
+// NextCtx is Next with a context that cancels the request for the page when done. With Prefetch the
+// request for the following page is cancelled by Close rather than ctx, and is made again with ctx if it fails.
+// If ctx is done while waiting for the prefetched page, ctx.Err() is returned and the prefetch is kept.
+func (c *Cursor[T]) NextCtx(ctx context.Context) ([]T, error) {
+	if c.done {
+		return nil, io.EOF
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if !c.started && c.options.Total == TotalProbe {
+		probe := Pagination{Limit: 1, IncludeTotal: true}
+		if err := getRequestWithParams(ctx, c.client, c.path, c.query, c.options.Params, &probe, c.orderBy, &[]T{}); err != nil {
+			return nil, err
+		}
+		c.total, c.hasTotal = probe.Total, true
+	}
+	c.started = true
+
+	var page cursorPage[T]
+	prefetched := false
+	if c.pending != nil {
+		select {
+		case page = <-c.pending:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		c.cancelPending()
+		c.pending = nil
+		// A failed prefetch is made again with ctx, so that a transient error is not returned for a request
+		// the caller did not make
+		prefetched = page.err == nil
+	}
+	if !prefetched {
+		page = c.fetch(ctx, c.offset)
+	}
+	if page.err != nil {
+		return nil, page.err
+	}
+
+	if page.total >= 0 {
+		c.total, c.hasTotal = page.total, true
+	}
+	c.pageOffset = c.offset
+	c.offset += len(page.items)
+	if c.limit <= 0 || len(page.items) < c.limit || (c.hasTotal && c.total > 0 && c.offset >= c.total) {
+		c.done = true
+	}
+	if len(page.items) == 0 {
+		return nil, io.EOF
+	}
+
+	if c.options.Prefetch && !c.done {
+		pending := make(chan cursorPage[T], 1)
+		prefetchCtx, cancel := context.WithCancel(c.ctx)
+		go func(offset int) {
+			pending <- c.fetch(prefetchCtx, offset)
+		}(c.offset)
+		c.pending, c.cancelPending = pending, cancel
+	}
+	if c.lastPageEOF && c.done {
+		return page.items, io.EOF
+	}
+	return page.items, nil
+}
+
+// fetchFirst fetches the first page with ctx, for Next to return, so that the total is known at once
+func (c *Cursor[T]) fetchFirst(ctx context.Context) error {
+	page := c.fetch(ctx, c.offset)
+	if page.err != nil {
+		return page.err
+	}
+	c.started = true
+	if page.total >= 0 {
+		c.total, c.hasTotal = page.total, true
+	}
+	pending := make(chan cursorPage[T], 1)
+	pending <- page
+	c.pending, c.cancelPending = pending, func() {}
+	return nil
+}
+
+// fetch requests the page at offset, asking for the total with the first page if the strategy is
+// TotalFromFirstPage. The total of the page is -1 if it was not asked for.
+func (c *Cursor[T]) fetch(ctx context.Context, offset int) cursorPage[T] {
+	pagination := Pagination{Limit: c.limit, Offset: offset}
+	pagination.IncludeTotal = c.options.Total == TotalFromFirstPage && !c.hasTotal
+	items := []T{}
+	err := getRequestWithParams(ctx, c.client, c.path, c.query, c.options.Params, &pagination, c.orderBy, &items)
+	page := cursorPage[T]{items: items, total: -1, err: err}
+	if pagination.IncludeTotal {
+		page.total = pagination.Total
+	}
+	return page
+}
+
+// Total returns the total number of results and true once it is known, which depends on the TotalStrategy
+func (c *Cursor[T]) Total() (int, bool) {
+	return c.total, c.hasTotal
+}
+
+// TotalPages returns the number of pages of the total number of results, or 0 until the total is known
+func (c *Cursor[T]) TotalPages() int {
+	if !c.hasTotal || c.total <= 0 {
+		return 0
+	}
+	if c.limit <= 0 {
+		return 1
+	}
+	return (c.total + c.limit - 1) / c.limit
+}
+
+// CurrentPage returns the number of the page last returned, counting from 1, or 1 before the first page
+func (c *Cursor[T]) CurrentPage() int {
+	if c.limit <= 0 {
+		return 1
+	}
+	return c.pageOffset/c.limit + 1
+}
+
+// Close cancels the request for a prefetched page, if there is one. The cursor returns io.EOF afterwards.
+func (c *Cursor[T]) Close() {
+	c.cancel()
+	c.pending = nil
+	c.done = true
+}
+
+// Pages returns an iterator over the remaining pages, which can be ranged over with Go 1.23 or later.
+// An error ends the iteration, after it is yielded with a nil page.
+func (c *Cursor[T]) Pages(ctx context.Context) func(yield func([]T, error) bool) {
+	return func(yield func([]T, error) bool) {
+		for {
+			page, err := c.NextCtx(ctx)
+			if errors.Is(err, io.EOF) {
+				if len(page) > 0 {
+					yield(page, nil)
+				}
+				return
+			}
+			if !yield(page, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the remaining results, fetching pages as they are needed, which can be
+// ranged over with Go 1.23 or later. An error ends the iteration, after it is yielded with the zero T.
+func (c *Cursor[T]) All(ctx context.Context) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		c.Pages(ctx)(func(page []T, err error) bool {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return false
+			}
+			for _, item := range page {
+				if !yield(item, nil) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package puppetdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+// cursorRequests records the query parameters of the requests setupCursorResponder responds to
+type cursorRequests struct {
+	sync.Mutex
+	params []url.Values
+}
+
+func (r *cursorRequests) all() []url.Values {
+	r.Lock()
+	defer r.Unlock()
+	return append([]url.Values{}, r.params...)
+}
+
+// setupCursorResponder responds to requests for path with total nodes named 1 to total, a page at a time,
+// and with an error for the page at errorOffset if it is not negative
+func setupCursorResponder(path string, total, errorOffset int) *cursorRequests {
+	requests := &cursorRequests{}
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodGet, hostURL+path, func(r *http.Request) (*http.Response, error) {
+		params := r.URL.Query()
+		requests.Lock()
+		requests.params = append(requests.params, params)
+		requests.Unlock()
+
+		offset, _ := strconv.Atoi(params.Get("offset"))
+		limit, _ := strconv.Atoi(params.Get("limit"))
+		if offset == errorOffset {
+			return httpmock.NewStringResponse(http.StatusInternalServerError, "oops"), nil
+		}
+		nodes := []Node{}
+		for i := offset; i < total && (limit == 0 || i < offset+limit); i++ {
+			nodes = append(nodes, Node{Certname: fmt.Sprintf("%d.delivery.puppetlabs.net", i+1)})
+		}
+		response, err := httpmock.NewJsonResponse(http.StatusOK, nodes)
+		if err != nil {
+			return nil, err
+		}
+		if params.Get("include_total") == "true" {
+			response.Header.Set("X-Records", strconv.Itoa(total))
+		}
+		return response, nil
+	})
+	return requests
+}
+
+func certnames(nodes []Node) []string {
+	var names []string
+	for _, node := range nodes {
+		names = append(names, node.Certname)
+	}
+	return names
+}
+
+func TestCursor(t *testing.T) {
+	requests := setupCursorResponder(nodes, 10, -1)
+	cursor := NewCursor[Node](pdbClient, "nodes", `["=", "facts_environment", "production"]`, &Pagination{Limit: 5}, nil, nil)
+	_, known := cursor.Total()
+	require.False(t, known)
+
+	page, err := cursor.Next()
+	require.NoError(t, err)
+	require.Len(t, page, 5)
+	require.Equal(t, "1.delivery.puppetlabs.net", page[0].Certname)
+	total, known := cursor.Total()
+	require.True(t, known)
+	require.Equal(t, 10, total)
+
+	// Test the last page comes without io.EOF
+	page, err = cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, "6.delivery.puppetlabs.net", page[0].Certname)
+
+	page, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+	require.Nil(t, page)
+
+	// Test the total is asked for with the first page only, without a probe
+	params := requests.all()
+	require.Len(t, params, 2)
+	require.Equal(t, "true", params[0].Get("include_total"))
+	require.Equal(t, `["=", "facts_environment", "production"]`, params[0].Get("query"))
+	require.Equal(t, "", params[1].Get("include_total"))
+	require.Equal(t, "5", params[1].Get("offset"))
+}
+
+func TestCursorTotalStrategies(t *testing.T) {
+	// Test the probe is made before the first page
+	requests := setupCursorResponder(nodes, 7, -1)
+	cursor := NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, &CursorOptions{Total: TotalProbe})
+	page, err := cursor.Next()
+	require.NoError(t, err)
+	require.Len(t, page, 5)
+	total, known := cursor.Total()
+	require.True(t, known)
+	require.Equal(t, 7, total)
+	page, err = cursor.Next()
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	_, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+	params := requests.all()
+	require.Len(t, params, 3)
+	require.Equal(t, "1", params[0].Get("limit"))
+
+	// Test without a total the cursor stops at a short or empty page
+	for _, count := range []int{7, 10} {
+		requests = setupCursorResponder(nodes, count, -1)
+		cursor = NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, &CursorOptions{Total: TotalNone})
+		var all []Node
+		for {
+			page, err := cursor.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			all = append(all, page...)
+		}
+		require.Len(t, all, count)
+		_, known = cursor.Total()
+		require.False(t, known)
+		for _, params := range requests.all() {
+			require.Equal(t, "", params.Get("include_total"))
+		}
+	}
+	require.Len(t, requests.all(), 3)
+}
+
+func TestCursorPrefetch(t *testing.T) {
+	requests := setupCursorResponder(nodes, 12, -1)
+	cursor := NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, &CursorOptions{Prefetch: true})
+	defer cursor.Close()
+
+	page, err := cursor.Next()
+	require.NoError(t, err)
+	require.Len(t, page, 5)
+
+	// Test the second page is requested before it is asked for
+	require.Eventually(t, func() bool { return len(requests.all()) == 2 }, time.Second, time.Millisecond)
+	page, err = cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, "6.delivery.puppetlabs.net", page[0].Certname)
+
+	require.Eventually(t, func() bool { return len(requests.all()) == 3 }, time.Second, time.Millisecond)
+	page, err = cursor.Next()
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+
+	// Test there is no prefetch past the last page
+	_, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+	require.Len(t, requests.all(), 3)
+}
+
+func TestCursorPrefetchContext(t *testing.T) {
+	// Test the prefetch outlives the context of the previous page
+	requests := setupCursorResponder(nodes, 10, -1)
+	cursor := NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, &CursorOptions{Prefetch: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := cursor.NextCtx(ctx)
+	require.NoError(t, err)
+	cancel()
+
+	page, err := cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, "6.delivery.puppetlabs.net", page[0].Certname)
+	require.Len(t, requests.all(), 2)
+
+	// Test Close cancels the prefetch
+	httpmock.Reset()
+	prefetched := make(chan error, 1)
+	httpmock.RegisterResponder(http.MethodGet, hostURL+nodes, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Query().Get("offset") == "" {
+			return httpmock.NewJsonResponse(http.StatusOK, []Node{{Certname: "1.delivery.puppetlabs.net"}, {}, {}, {}, {}})
+		}
+		<-r.Context().Done()
+		prefetched <- r.Context().Err()
+		return nil, r.Context().Err()
+	})
+	cursor = NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, &CursorOptions{Prefetch: true, Total: TotalNone})
+	_, err = cursor.Next()
+	require.NoError(t, err)
+	cursor.Close()
+	select {
+	case err := <-prefetched:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the prefetch was not cancelled by Close")
+	}
+	_, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestCursorPrefetchWait(t *testing.T) {
+	httpmock.Reset()
+	release := make(chan struct{})
+	httpmock.RegisterResponder(http.MethodGet, hostURL+nodes, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Query().Get("offset") == "" {
+			return httpmock.NewJsonResponse(http.StatusOK, []Node{{}, {}, {}, {}, {}})
+		}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, []Node{{Certname: "6.delivery.puppetlabs.net"}})
+	})
+	cursor := NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, &CursorOptions{Prefetch: true, Total: TotalNone})
+	defer cursor.Close()
+	_, err := cursor.Next()
+	require.NoError(t, err)
+
+	// Test waiting for the prefetched page ends with ctx
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = cursor.NextCtx(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Test the prefetch is kept for the next call
+	close(release)
+	page, err := cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, "6.delivery.puppetlabs.net", page[0].Certname)
+	require.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestCursorAll(t *testing.T) {
+	setupCursorResponder(nodes, 12, -1)
+	cursor := NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, &CursorOptions{Prefetch: true})
+	var all []Node
+	cursor.All(context.Background())(func(node Node, err error) bool {
+		require.NoError(t, err)
+		all = append(all, node)
+		return true
+	})
+	require.Len(t, all, 12)
+	require.Equal(t, "12.delivery.puppetlabs.net", all[11].Certname)
+
+	// Test breaking out of the loop stops fetching
+	requests := setupCursorResponder(nodes, 12, -1)
+	cursor = NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, nil)
+	all = nil
+	cursor.All(context.Background())(func(node Node, err error) bool {
+		all = append(all, node)
+		return len(all) < 3
+	})
+	require.Len(t, all, 3)
+	require.Len(t, requests.all(), 1)
+
+	// Test an error ends the loop
+	setupCursorResponder(nodes, 12, 5)
+	cursor = NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, nil)
+	var errs []error
+	all = nil
+	cursor.All(context.Background())(func(node Node, err error) bool {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			all = append(all, node)
+		}
+		return true
+	})
+	require.Len(t, all, 5)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0], ErrNonTransientResponse)
+}
+
+func TestCursorPages(t *testing.T) {
+	setupCursorResponder(nodes, 7, -1)
+	cursor := NewCursor[Node](pdbClient, "nodes", "", &Pagination{Limit: 5}, nil, nil)
+	var pages [][]string
+	cursor.Pages(context.Background())(func(page []Node, err error) bool {
+		require.NoError(t, err)
+		pages = append(pages, certnames(page))
+		return true
+	})
+	require.Len(t, pages, 2)
+	require.Len(t, pages[1], 2)
+}
+
+func TestCursorEndpoints(t *testing.T) {
+	// Test the root endpoint, a full path and every result in one page
+	setupCursorResponder(rootQueryEndpoint, 3, -1)
+	cursor := NewCursor[map[string]interface{}](pdbClient, "", `["from", "nodes"]`, &Pagination{}, nil, nil)
+	page, err := cursor.Next()
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	_, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+
+	requests := setupCursorResponder(nodes, 3, -1)
+	cursor2 := NewCursor[Node](pdbClient, nodes, "", nil, nil, nil)
+	page2, err := cursor2.Next()
+	require.NoError(t, err)
+	require.Len(t, page2, 3)
+	require.Equal(t, "100", requests.all()[0].Get("limit"))
+
+	// Test the endpoint parameters are sent
+	setupGetResponderWithParams(t, eventCounts, map[string]string{
+		"summarize_by":  "certname",
+		"limit":         "100",
+		"include_total": "true",
+	}, "event-counts-response.json")
+	counts := NewCursor[EventCount](pdbClient, "event-counts", "", nil, nil, &CursorOptions{Params: map[string]string{"summarize_by": "certname"}})
+	page3, err := counts.Next()
+	require.NoError(t, err)
+	require.Equal(t, expectedEventCounts, page3)
+}
+
+func TestPaginatedCursor(t *testing.T) {
+	// Test the first page is fetched with the total, without a probe
+	requests := setupCursorResponder(nodes, 7, -1)
+	cursor, err := pdbClient.PaginatedNodes("", &Pagination{Limit: 5}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, cursor.TotalPages())
+	require.Equal(t, 1, cursor.CurrentPage())
+	params := requests.all()
+	require.Len(t, params, 1)
+	require.Equal(t, "5", params[0].Get("limit"))
+	require.Equal(t, "true", params[0].Get("include_total"))
+
+	// Test the last page is yielded although it comes with io.EOF
+	var pages [][]string
+	cursor.Pages(context.Background())(func(page []Node, err error) bool {
+		require.NoError(t, err)
+		pages = append(pages, certnames(page))
+		return true
+	})
+	require.Len(t, pages, 2)
+	require.Len(t, pages[1], 2)
+	require.Len(t, requests.all(), 2)
+
+	// Test a root query is decoded into the target
+	setupCursorResponder(rootQueryEndpoint, 7, -1)
+	root, err := pdbClient.PaginatedRootQuery(`["from", "nodes"]`, &Pagination{Limit: 5}, nil)
+	require.NoError(t, err)
+	var page []Node
+	require.NoError(t, root.NextInto(&page))
+	require.Len(t, page, 5)
+	require.Equal(t, "1.delivery.puppetlabs.net", page[0].Certname)
+	page = nil
+	require.ErrorIs(t, root.NextInto(&page), io.EOF)
+	require.Equal(t, []string{"6.delivery.puppetlabs.net", "7.delivery.puppetlabs.net"}, certnames(page))
+}
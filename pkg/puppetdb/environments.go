@@ -1,13 +1,20 @@
 package puppetdb
 
+import "context"
+
 const (
 	environments = "/pdb/query/v4/environments"
 )
 
 // Environments returns a list of all known environments
 func (c *Client) Environments() ([]Environment, error) {
+	return c.EnvironmentsCtx(context.Background())
+}
+
+// EnvironmentsCtx is Environments with a context that cancels the request when done
+func (c *Client) EnvironmentsCtx(ctx context.Context) ([]Environment, error) {
 	payload := []Environment{}
-	err := getRequest(c, environments, "", nil, nil, &payload)
+	err := getRequest(ctx, c, environments, "", nil, nil, &payload)
 	return payload, err
 }
 
@@ -0,0 +1,177 @@
+package puppetdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	events               = "/pdb/query/v4/events"
+	eventCounts          = "/pdb/query/v4/event-counts"
+	aggregateEventCounts = "/pdb/query/v4/aggregate-event-counts"
+)
+
+// What event counts can be summarized and counted by
+const (
+	SummarizeByCertname        = "certname"
+	SummarizeByResource        = "resource"
+	SummarizeByContainingClass = "containing_class"
+
+	CountByCertname = "certname"
+	CountByResource = "resource"
+)
+
+// ErrSummarizeByRequired is returned by EventCounts and AggregateEventCounts when no SummarizeBy is given
+var ErrSummarizeByRequired = errors.New("puppetdb: summarize_by is required to count events")
+
+// Events will return the resource events of the reports matching the given query,
+// e.g. ["=", "status", "failure"] for the failed changes
+func (c *Client) Events(query string, pagination *Pagination, orderBy *OrderBy) ([]ResourceEvent, error) {
+	return c.EventsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// EventsCtx is Events with a context that cancels the request when done
+func (c *Client) EventsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]ResourceEvent, error) {
+	payload := []ResourceEvent{}
+	err := getRequest(ctx, c, events, query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// PaginatedEvents works just like Events, but returns an EventsCursor that
+// provides methods for iterating over N pages of events. If pagination is
+// nil, then a default configuration with a limit of 100 is used instead.
+func (c *Client) PaginatedEvents(query string, pagination *Pagination, orderBy *OrderBy) (*EventsCursor, error) {
+	return c.PaginatedEventsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedEventsCtx is PaginatedEvents with a context that cancels the request for the first page of events,
+// use NextCtx to fetch the other pages with a context
+func (c *Client) PaginatedEventsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*EventsCursor, error) {
+	cursor, err := newPageCursor[ResourceEvent](ctx, c, events, query, pagination, orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
+	}
+
+	return &EventsCursor{Cursor: cursor}, nil
+}
+
+// EventCounts will return the number of events matching the given query for each certname, resource or
+// containing class, as set by options.SummarizeBy
+func (c *Client) EventCounts(query string, options EventCountsOptions, pagination *Pagination, orderBy *OrderBy) ([]EventCount, error) {
+	return c.EventCountsCtx(context.Background(), query, options, pagination, orderBy)
+}
+
+// EventCountsCtx is EventCounts with a context that cancels the request when done
+func (c *Client) EventCountsCtx(ctx context.Context, query string, options EventCountsOptions, pagination *Pagination, orderBy *OrderBy) ([]EventCount, error) {
+	params, err := options.toParams()
+	if err != nil {
+		return nil, err
+	}
+	payload := []EventCount{}
+	err = getRequestWithParams(ctx, c, eventCounts, query, params, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// AggregateEventCounts will return the totals of EventCounts. options.SummarizeBy may list several of
+// certname, resource and containing_class separated by commas, returning the totals for each.
+func (c *Client) AggregateEventCounts(query string, options EventCountsOptions) ([]AggregateEventCount, error) {
+	return c.AggregateEventCountsCtx(context.Background(), query, options)
+}
+
+// AggregateEventCountsCtx is AggregateEventCounts with a context that cancels the request when done
+func (c *Client) AggregateEventCountsCtx(ctx context.Context, query string, options EventCountsOptions) ([]AggregateEventCount, error) {
+	params, err := options.toParams()
+	if err != nil {
+		return nil, err
+	}
+	payload := []AggregateEventCount{}
+	err = getRequestWithParams(ctx, c, aggregateEventCounts, query, params, nil, nil, &payload)
+	return payload, err
+}
+
+// EventCountsOptions are the parameters of the event-counts and aggregate-event-counts endpoints.
+// SummarizeBy is required, the others are optional.
+type EventCountsOptions struct {
+	// SummarizeBy is what the events are counted for, one of the SummarizeBy constants
+	SummarizeBy string
+	// CountBy is what is counted, events by default or one of the CountBy constants
+	CountBy string
+	// CountsFilter is a query on the counts, e.g. [">", "failures", 0]
+	CountsFilter string
+}
+
+func (o EventCountsOptions) toParams() (map[string]string, error) {
+	if o.SummarizeBy == "" {
+		return nil, ErrSummarizeByRequired
+	}
+	params := map[string]string{"summarize_by": o.SummarizeBy}
+	if o.CountBy != "" {
+		params["count_by"] = o.CountBy
+	}
+	if o.CountsFilter != "" {
+		params["counts_filter"] = o.CountsFilter
+	}
+	return params, nil
+}
+
+// ResourceEvent is a change, or attempted change, to a resource during a Puppet run.
+// Status is one of success, failure, noop or skipped.
+type ResourceEvent struct {
+	Certname             string      `json:"certname"`
+	ConfigurationVersion string      `json:"configuration_version"`
+	ContainingClass      string      `json:"containing_class"`
+	ContainmentPath      []string    `json:"containment_path"`
+	CorrectiveChange     bool        `json:"corrective_change"`
+	Environment          string      `json:"environment"`
+	File                 string      `json:"file"`
+	Line                 int         `json:"line"`
+	Message              string      `json:"message"`
+	Name                 string      `json:"name"`
+	NewValue             interface{} `json:"new_value"`
+	OldValue             interface{} `json:"old_value"`
+	Property             string      `json:"property"`
+	Report               string      `json:"report"`
+	ReportReceiveTime    time.Time   `json:"report_receive_time"`
+	ResourceTitle        string      `json:"resource_title"`
+	ResourceType         string      `json:"resource_type"`
+	RunEndTime           time.Time   `json:"run_end_time"`
+	RunStartTime         time.Time   `json:"run_start_time"`
+	Status               string      `json:"status"`
+	Timestamp            time.Time   `json:"timestamp"`
+	Count                int         `json:"count,omitempty"`
+}
+
+// EventCount is the number of events of each status for a subject, a certname, resource or containing class
+type EventCount struct {
+	SubjectType string       `json:"subject_type"`
+	Subject     EventSubject `json:"subject"`
+	Successes   int          `json:"successes"`
+	Failures    int          `json:"failures"`
+	Noops       int          `json:"noops"`
+	Skips       int          `json:"skips"`
+}
+
+// EventSubject is what events were counted for. Type is only set for a resource, Title is the certname,
+// resource title or class name.
+type EventSubject struct {
+	Type  string `json:"type,omitempty"`
+	Title string `json:"title"`
+}
+
+// AggregateEventCount is the number of subjects with events of each status, and in total
+type AggregateEventCount struct {
+	SummarizeBy string `json:"summarize_by"`
+	Successes   int    `json:"successes"`
+	Failures    int    `json:"failures"`
+	Noops       int    `json:"noops"`
+	Skips       int    `json:"skips"`
+	Total       int    `json:"total"`
+}
+
+// EventsCursor is a pagination cursor that provides convenience methods for
+// stepping through pages of events.
+type EventsCursor struct {
+	*Cursor[ResourceEvent]
+}
@@ -0,0 +1,126 @@
+package puppetdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	query := `["=", "status", "failure"]`
+	setupGetResponder(t, events, "query="+query, "events-response.json")
+	actual, err := pdbClient.Events(query, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedEvents, actual)
+}
+
+func TestPaginatedEvents(t *testing.T) {
+	pagination := Pagination{Limit: 1, IncludeTotal: true}
+	setupPaginatedGetResponder(t, events, "", mockPaginatedGetOptions{
+		limit:         pagination.Limit,
+		total:         2,
+		pageFilenames: []string{"events-response.json", "events-response.json"},
+	})
+
+	cursor, err := pdbClient.PaginatedEvents("", &pagination, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, cursor.TotalPages())
+
+	actual, err := cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, expectedEvents, actual)
+
+	_, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestEventCounts(t *testing.T) {
+	// Test the parameters are sent
+	query := `["=", "status", "failure"]`
+	setupGetResponderWithParams(t, eventCounts, map[string]string{
+		"query":         query,
+		"summarize_by":  "resource",
+		"count_by":      "certname",
+		"counts_filter": `[">", "failures", 0]`,
+		"limit":         "10",
+	}, "event-counts-response.json")
+	actual, err := pdbClient.EventCounts(query, EventCountsOptions{
+		SummarizeBy:  SummarizeByResource,
+		CountBy:      CountByCertname,
+		CountsFilter: `[">", "failures", 0]`,
+	}, &Pagination{Limit: 10}, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedEventCounts, actual)
+
+	// Test summarize_by is required
+	_, err = pdbClient.EventCounts(query, EventCountsOptions{}, nil, nil)
+	require.ErrorIs(t, err, ErrSummarizeByRequired)
+
+	// Test deadline reaches the request
+	setupBlockingResponder(eventCounts)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pdbClient.EventCountsCtx(ctx, "", EventCountsOptions{SummarizeBy: SummarizeByCertname}, nil, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAggregateEventCounts(t *testing.T) {
+	setupGetResponderWithParams(t, aggregateEventCounts, map[string]string{
+		"summarize_by": "certname,resource",
+	}, "aggregate-event-counts-response.json")
+	actual, err := pdbClient.AggregateEventCounts("", EventCountsOptions{SummarizeBy: "certname,resource"})
+	require.Nil(t, err)
+	require.Equal(t, expectedAggregateEventCounts, actual)
+
+	_, err = pdbClient.AggregateEventCounts("", EventCountsOptions{})
+	require.ErrorIs(t, err, ErrSummarizeByRequired)
+}
+
+// setupGetResponderWithParams responds to a request for url with exactly the query parameters params
+func setupGetResponderWithParams(t *testing.T, url string, params map[string]string, responseFilename string) {
+	httpmock.Reset()
+	responseBody, err := os.ReadFile("testdata/" + responseFilename)
+	require.Nil(t, err)
+	response := httpmock.NewBytesResponse(http.StatusOK, responseBody)
+	response.Header.Set("Content-Type", "application/json")
+	httpmock.RegisterResponderWithQuery(http.MethodGet, hostURL+url, params, httpmock.ResponderFromResponse(response))
+	response.Body.Close()
+}
+
+var expectedEvents = []ResourceEvent{{
+	Certname:             "lenient-veranda.delivery.puppetlabs.net",
+	ConfigurationVersion: "1584699453",
+	ContainingClass:      "Ntp::Service",
+	ContainmentPath:      []string{"Stage[main]", "Ntp::Service", "Service[ntpd]"},
+	Environment:          "production",
+	File:                 "/etc/puppetlabs/code/environments/production/modules/ntp/manifests/service.pp",
+	Line:                 8,
+	Message:              "Could not enable ntpd",
+	NewValue:             "running",
+	OldValue:             "stopped",
+	Property:             "ensure",
+	Report:               "7ccb6fb17b3fe11cecffe00b43b44f3776bcb89d",
+	ReportReceiveTime:    time.Date(2020, 3, 20, 10, 17, 54, 470000000, time.UTC),
+	ResourceTitle:        "ntpd",
+	ResourceType:         "Service",
+	RunEndTime:           time.Date(2020, 3, 20, 10, 17, 52, 0, time.UTC),
+	RunStartTime:         time.Date(2020, 3, 20, 10, 17, 30, 0, time.UTC),
+	Status:               "failure",
+	Timestamp:            time.Date(2020, 3, 20, 10, 17, 50, 0, time.UTC),
+}}
+
+var expectedEventCounts = []EventCount{
+	{SubjectType: "resource", Subject: EventSubject{Type: "Service", Title: "ntpd"}, Failures: 2, Skips: 1},
+	{SubjectType: "resource", Subject: EventSubject{Type: "File", Title: "/etc/ntp.conf"}, Failures: 1, Successes: 3},
+}
+
+var expectedAggregateEventCounts = []AggregateEventCount{
+	{SummarizeBy: "certname", Successes: 1, Failures: 2, Skips: 1, Total: 3},
+	{SummarizeBy: "resource", Successes: 1, Failures: 2, Skips: 1, Total: 2},
+}
@@ -1,9 +1,8 @@
 package puppetdb
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"io"
 )
 
 const (
@@ -15,43 +14,65 @@ const (
 
 // FactNames will return an alphabetical list of all known fact names, including those which are known only for deactivated nodes.
 func (c *Client) FactNames(pagination *Pagination, orderBy *OrderBy) ([]string, error) {
+	return c.FactNamesCtx(context.Background(), pagination, orderBy)
+}
+
+// FactNamesCtx is FactNames with a context that cancels the request when done
+func (c *Client) FactNamesCtx(ctx context.Context, pagination *Pagination, orderBy *OrderBy) ([]string, error) {
 	payload := []string{}
-	err := getRequest(c, factNames, "", pagination, orderBy, &payload)
+	err := getRequest(ctx, c, factNames, "", pagination, orderBy, &payload)
 	return payload, err
 }
 
 // FactPaths will return a set of all known fact paths for all known nodes, and is intended as a counterpart to the fact-names endpoint.
 func (c *Client) FactPaths(query string, pagination *Pagination, orderBy *OrderBy) ([]FactPath, error) {
+	return c.FactPathsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// FactPathsCtx is FactPaths with a context that cancels the request when done
+func (c *Client) FactPathsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]FactPath, error) {
 	payload := []FactPath{}
-	err := getRequest(c, factPaths, query, pagination, orderBy, &payload)
+	err := getRequest(ctx, c, factPaths, query, pagination, orderBy, &payload)
 	return payload, err
 }
 
 // Facts will return all facts matching the given query. Facts for deactivated nodes are not included in the response.
 func (c *Client) Facts(query string, pagination *Pagination, orderBy *OrderBy) ([]Fact, error) {
+	return c.FactsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// FactsCtx is Facts with a context that cancels the request when done
+func (c *Client) FactsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Fact, error) {
 	payload := []Fact{}
-	err := getRequest(c, facts, query, pagination, orderBy, &payload)
+	err := getRequest(ctx, c, facts, query, pagination, orderBy, &payload)
 	return payload, err
 }
 
 func (c *Client) PaginatedFacts(query string, pagination *Pagination, orderBy *OrderBy) (*FactsCursor, error) {
-	pc, err := newPageCursor(c, facts, query, pagination, orderBy)
+	return c.PaginatedFactsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedFactsCtx is PaginatedFacts with a context that cancels the request for the first page of facts,
+// use NextCtx to fetch the other pages with a context
+func (c *Client) PaginatedFactsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*FactsCursor, error) {
+	cursor, err := newPageCursor[Fact](ctx, c, facts, query, pagination, orderBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
 	}
 
-	cursor := FactsCursor{
-		pageCursor: pc,
-	}
-
-	return &cursor, nil
+	return &FactsCursor{Cursor: cursor}, nil
 }
 
 // FactContents will return all facts matching the given query on the fact-contents endpoint. Facts for deactivated nodes are not included in the response.
 // - https://puppet.com/docs/puppetdb/latest/api/query/v4/fact-contents.html
 func (c *Client) FactContents(query string, pagination *Pagination, orderBy *OrderBy) ([]Fact, error) {
+	return c.FactContentsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// FactContentsCtx is FactContents with a context that cancels the request when done
+func (c *Client) FactContentsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Fact, error) {
 	payload := []Fact{}
-	err := getRequest(c, factContents, query, pagination, orderBy, &payload)
+	err := getRequest(ctx, c, factContents, query, pagination, orderBy, &payload)
 	return payload, err
 }
 
@@ -81,15 +102,5 @@ type FactPath struct {
 }
 
 type FactsCursor struct {
-	*pageCursor
-}
-
-func (fc FactsCursor) Next() ([]Fact, error) {
-	payload := []Fact{}
-	err := fc.next(&payload)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return nil, err
-	}
-
-	return payload, err
+	*Cursor[Fact]
 }
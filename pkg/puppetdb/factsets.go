@@ -0,0 +1,148 @@
+package puppetdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+)
+
+const (
+	factsets  = "/pdb/query/v4/factsets"
+	factset   = "/pdb/query/v4/factsets/{certname}"
+	producers = "/pdb/query/v4/producers"
+)
+
+// Factsets will return the most recent facts of each node matching the given query, as a tree per node
+func (c *Client) Factsets(query string, pagination *Pagination, orderBy *OrderBy) ([]Factset, error) {
+	return c.FactsetsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// FactsetsCtx is Factsets with a context that cancels the request when done
+func (c *Client) FactsetsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Factset, error) {
+	payload := []Factset{}
+	err := getRequest(ctx, c, factsets, query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// PaginatedFactsets works just like Factsets, but returns a FactsetsCursor that
+// provides methods for iterating over N pages of factsets. If pagination is
+// nil, then a default configuration with a limit of 100 is used instead.
+func (c *Client) PaginatedFactsets(query string, pagination *Pagination, orderBy *OrderBy) (*FactsetsCursor, error) {
+	return c.PaginatedFactsetsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedFactsetsCtx is PaginatedFactsets with a context that cancels the request for the first page of factsets,
+// use NextCtx to fetch the other pages with a context
+func (c *Client) PaginatedFactsetsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*FactsetsCursor, error) {
+	cursor, err := newPageCursor[Factset](ctx, c, factsets, query, pagination, orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
+	}
+
+	return &FactsetsCursor{Cursor: cursor}, nil
+}
+
+// Factset will return the most recent facts of a node by certname. An error matching apierror.ErrNotFound
+// is returned if the node has no facts.
+func (c *Client) Factset(certname string) (*Factset, error) {
+	return c.FactsetCtx(context.Background(), certname)
+}
+
+// FactsetCtx is Factset with a context that cancels the request when done
+func (c *Client) FactsetCtx(ctx context.Context, certname string) (*Factset, error) {
+	// The endpoint responds with an array like factsets, restricted to the node
+	path := strings.ReplaceAll(factset, "{certname}", url.PathEscape(certname))
+	payload := []Factset{}
+	if err := getRequest(ctx, c, path, "", nil, nil, &payload); err != nil {
+		return nil, err
+	}
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("%s: no facts for %s: %w", path, certname, apierror.ErrNotFound)
+	}
+	return &payload[0], nil
+}
+
+// Producers will return the Puppet servers that submit catalogs, facts and reports matching the given query
+func (c *Client) Producers(query string, pagination *Pagination, orderBy *OrderBy) ([]Producer, error) {
+	return c.ProducersCtx(context.Background(), query, pagination, orderBy)
+}
+
+// ProducersCtx is Producers with a context that cancels the request when done
+func (c *Client) ProducersCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Producer, error) {
+	payload := []Producer{}
+	err := getRequest(ctx, c, producers, query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// Factset is the facts of a node as submitted together by a producer. Hash is the hash of the facts,
+// which changes only when they do.
+type Factset struct {
+	Certname          string       `json:"certname"`
+	Environment       string       `json:"environment"`
+	Timestamp         time.Time    `json:"timestamp"`
+	ProducerTimestamp time.Time    `json:"producer_timestamp"`
+	Producer          string       `json:"producer"`
+	Hash              string       `json:"hash"`
+	Facts             FactsetFacts `json:"facts"`
+	Count             int          `json:"count,omitempty"`
+}
+
+// FactsetFacts are the facts of a factset, Href is where they can be queried from
+type FactsetFacts struct {
+	Href string        `json:"href"`
+	Data []FactsetFact `json:"data"`
+}
+
+// FactsetFact is a top level fact of a factset, Value holds any structured facts below it
+type FactsetFact struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// Map returns the facts as a tree of nested maps and slices keyed by fact name,
+// e.g. facts["os"].(map[string]interface{})["family"]
+func (f Factset) Map() map[string]interface{} {
+	facts := make(map[string]interface{}, len(f.Facts.Data))
+	for _, fact := range f.Facts.Data {
+		facts[fact.Name] = fact.Value
+	}
+	return facts
+}
+
+// Decode decodes the facts into v, a pointer to a struct with JSON tags for the facts it needs or a map.
+//
+//	var facts struct {
+//		OS struct {
+//			Family string `json:"family"`
+//		} `json:"os"`
+//		Processors struct {
+//			Count int `json:"count"`
+//		} `json:"processors"`
+//	}
+//	err := factset.Decode(&facts)
+func (f Factset) Decode(v interface{}) error {
+	b, err := json.Marshal(f.Map())
+	if err != nil {
+		return fmt.Errorf("facts of %s: %w", f.Certname, err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("facts of %s: %w", f.Certname, err)
+	}
+	return nil
+}
+
+// Producer is a Puppet server that submits catalogs, facts and reports to PuppetDB
+type Producer struct {
+	Name string `json:"name"`
+}
+
+// FactsetsCursor is a pagination cursor that provides convenience methods for
+// stepping through pages of factsets.
+type FactsetsCursor struct {
+	*Cursor[Factset]
+}
@@ -0,0 +1,113 @@
+package puppetdb
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactsets(t *testing.T) {
+	query := `["=", "certname", "lenient-veranda.delivery.puppetlabs.net"]`
+	setupGetResponder(t, factsets, "query="+query, "factsets-response.json")
+	actual, err := pdbClient.Factsets(query, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, []Factset{expectedFactset}, actual)
+}
+
+func TestPaginatedFactsets(t *testing.T) {
+	pagination := Pagination{Limit: 1, IncludeTotal: true}
+	setupPaginatedGetResponder(t, factsets, "", mockPaginatedGetOptions{
+		limit:         pagination.Limit,
+		total:         2,
+		pageFilenames: []string{"factsets-response.json", "factsets-response.json"},
+	})
+
+	cursor, err := pdbClient.PaginatedFactsets("", &pagination, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, cursor.TotalPages())
+
+	actual, err := cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, []Factset{expectedFactset}, actual)
+
+	_, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestFactset(t *testing.T) {
+	factsetURL := strings.ReplaceAll(factset, "{certname}", "lenient-veranda.delivery.puppetlabs.net")
+
+	// Test success
+	setupGetResponder(t, factsetURL, "", "factsets-response.json")
+	actual, err := pdbClient.Factset("lenient-veranda.delivery.puppetlabs.net")
+	require.Nil(t, err)
+	require.Equal(t, &expectedFactset, actual)
+
+	// Test a node without facts
+	factsetURL = strings.ReplaceAll(factset, "{certname}", "unknown")
+	setupGetResponder(t, factsetURL, "", "empty-response.json")
+	_, err = pdbClient.Factset("unknown")
+	require.True(t, apierror.IsNotFound(err))
+}
+
+func TestFactsetDecode(t *testing.T) {
+	facts := expectedFactset.Map()
+	require.Equal(t, "RedHat", facts["os"].(map[string]interface{})["family"])
+	require.Equal(t, true, facts["is_virtual"])
+
+	var decoded struct {
+		OS struct {
+			Family  string `json:"family"`
+			Release struct {
+				Major string `json:"major"`
+			} `json:"release"`
+		} `json:"os"`
+		Processors struct {
+			Count  int      `json:"count"`
+			Models []string `json:"models"`
+		} `json:"processors"`
+		IsVirtual bool `json:"is_virtual"`
+	}
+	require.Nil(t, expectedFactset.Decode(&decoded))
+	require.Equal(t, "RedHat", decoded.OS.Family)
+	require.Equal(t, "7", decoded.OS.Release.Major)
+	require.Equal(t, 2, decoded.Processors.Count)
+	require.Len(t, decoded.Processors.Models, 2)
+	require.True(t, decoded.IsVirtual)
+
+	// Test a fact of the wrong type
+	var wrong struct {
+		IsVirtual string `json:"is_virtual"`
+	}
+	err := expectedFactset.Decode(&wrong)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "facts of lenient-veranda.delivery.puppetlabs.net")
+}
+
+func TestProducers(t *testing.T) {
+	setupGetResponder(t, producers, "", "producers-response.json")
+	actual, err := pdbClient.Producers("", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, []Producer{{Name: "pe-master.delivery.puppetlabs.net"}, {Name: "pe-compiler.delivery.puppetlabs.net"}}, actual)
+}
+
+var expectedFactset = Factset{
+	Certname:          "lenient-veranda.delivery.puppetlabs.net",
+	Environment:       "production",
+	Timestamp:         time.Date(2020, 3, 20, 10, 17, 30, 394000000, time.UTC),
+	ProducerTimestamp: time.Date(2020, 3, 20, 10, 17, 30, 120000000, time.UTC),
+	Producer:          "pe-master.delivery.puppetlabs.net",
+	Hash:              "b1e7d2c6f4a9e8d7c6b5a4f3e2d1c0b9a8f7e6d5",
+	Facts: FactsetFacts{
+		Href: "/pdb/query/v4/factsets/lenient-veranda.delivery.puppetlabs.net/facts",
+		Data: []FactsetFact{
+			{Name: "os", Value: map[string]interface{}{"family": "RedHat", "release": map[string]interface{}{"full": "7.8.2003", "major": "7"}}},
+			{Name: "processors", Value: map[string]interface{}{"count": float64(2), "models": []interface{}{"Intel(R) Xeon(R) CPU", "Intel(R) Xeon(R) CPU"}}},
+			{Name: "is_virtual", Value: true},
+		},
+	},
+}
@@ -1,5 +1,7 @@
 package puppetdb
 
+import "context"
+
 const (
 	inventory = "/pdb/query/v4/inventory"
 )
@@ -7,16 +9,26 @@ const (
 // Inventory enables an alternative query syntax for digging into structured facts, and can be used instead of the facts,
 // fact-contents, and factsets endpoints for most fact-related queries.
 func (c *Client) Inventory(query string, pagination *Pagination, orderBy *OrderBy) ([]Inventory, error) {
+	return c.InventoryCtx(context.Background(), query, pagination, orderBy)
+}
+
+// InventoryCtx is Inventory with a context that cancels the request when done
+func (c *Client) InventoryCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Inventory, error) {
 	payload := []Inventory{}
-	err := getRequest(c, inventory, query, pagination, orderBy, &payload)
+	err := getRequest(ctx, c, inventory, query, pagination, orderBy, &payload)
 	return payload, err
 }
 
 // InventoryMap an alternative to Inventory which returns an Array of Maps which will allow for fields that do
 // not fit into the Inventory struct, i.e. dot notation fields.
 func (c *Client) InventoryMap(query string, pagination *Pagination, orderBy *OrderBy) ([]map[string]interface{}, error) {
+	return c.InventoryMapCtx(context.Background(), query, pagination, orderBy)
+}
+
+// InventoryMapCtx is InventoryMap with a context that cancels the request when done
+func (c *Client) InventoryMapCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]map[string]interface{}, error) {
 	var payload []map[string]interface{}
-	err := getRequest(c, inventory, query, pagination, orderBy, &payload)
+	err := getRequest(ctx, c, inventory, query, pagination, orderBy, &payload)
 	return payload, err
 }
 
@@ -1,10 +1,11 @@
 package puppetdb
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"io"
 	"strings"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 )
 
 const (
@@ -14,8 +15,13 @@ const (
 
 // Nodes will return all nodes matching the given query. Deactivated and expired nodes aren’t included in the response.
 func (c *Client) Nodes(query string, pagination *Pagination, orderBy *OrderBy) ([]Node, error) {
+	return c.NodesCtx(context.Background(), query, pagination, orderBy)
+}
+
+// NodesCtx is Nodes with a context that cancels the request when done
+func (c *Client) NodesCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Node, error) {
 	payload := []Node{}
-	err := getRequest(c, nodes, query, pagination, orderBy, &payload)
+	err := getRequest(ctx, c, nodes, query, pagination, orderBy, &payload)
 	return payload, err
 }
 
@@ -24,22 +30,30 @@ func (c *Client) Nodes(query string, pagination *Pagination, orderBy *OrderBy) (
 // information for tracking progress. If pagination is nil, then a default
 // configuration with a limit of 100 is used instead.
 func (c *Client) PaginatedNodes(query string, pagination *Pagination, orderBy *OrderBy) (*NodesCursor, error) {
-	pc, err := newPageCursor(c, nodes, query, pagination, orderBy)
+	return c.PaginatedNodesCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedNodesCtx is PaginatedNodes with a context that cancels the request for the first page of nodes,
+// use NextCtx to fetch the other pages with a context
+func (c *Client) PaginatedNodesCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*NodesCursor, error) {
+	cursor, err := newPageCursor[Node](ctx, c, nodes, query, pagination, orderBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
 	}
 
-	cursor := NodesCursor{
-		pageCursor: pc,
-	}
-
-	return &cursor, nil
+	return &NodesCursor{Cursor: cursor}, nil
 }
 
 // Node will return a single node by certname
 func (c *Client) Node(certname string) (*Node, error) {
+	return c.NodeCtx(context.Background(), certname)
+}
+
+// NodeCtx is Node with a context that cancels the request when done
+func (c *Client) NodeCtx(ctx context.Context, certname string) (*Node, error) {
 	payload := &Node{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetPathParams(map[string]string{"certname": certname}).
 		Get(node)
@@ -50,7 +64,8 @@ func (c *Client) Node(certname string) (*Node, error) {
 		if r.Error() != nil {
 			return nil, r.Error().(error)
 		}
-		return nil, fmt.Errorf("%s error: %s", strings.ReplaceAll(node, "{certname}", certname), r.Status())
+		return nil, fmt.Errorf("%s error: %s: %w", strings.ReplaceAll(node, "{certname}", certname), r.Status(),
+			apierror.FromResponse(apierror.ServicePuppetDB, r))
 	}
 	return payload, nil
 }
@@ -79,17 +94,5 @@ type Node struct {
 // NodesCursor is a pagination cursor that provides convenience methods for
 // stepping through pages of nodes.
 type NodesCursor struct {
-	*pageCursor
-}
-
-// Next returns a page of nodes and iterates the pagination cursor by the
-// offset. If there are no more results left, the error will be io.EOF.
-func (nc *NodesCursor) Next() ([]Node, error) {
-	payload := []Node{}
-	err := nc.next(&payload)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return nil, err
-	}
-
-	return payload, err
+	*Cursor[Node]
 }
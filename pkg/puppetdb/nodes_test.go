@@ -1,9 +1,11 @@
 package puppetdb
 
 import (
+	"context"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -23,6 +25,47 @@ func TestNodes(t *testing.T) {
 	require.Equal(t, expectedNodes, actual)
 }
 
+func TestNodesCtx(t *testing.T) {
+	// Test success
+	setupGetResponder(t, "/pdb/query/v4/nodes", "", "nodes-response.json")
+	actual, err := pdbClient.NodesCtx(context.Background(), "", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedNodes, actual)
+
+	// Test deadline reaches the request
+	setupBlockingResponder("/pdb/query/v4/nodes")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pdbClient.NodesCtx(ctx, "", nil, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = pdbClient.PaginatedNodesCtx(ctx, "", nil, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPaginatedNodesNextCtx(t *testing.T) {
+	pagination := Pagination{Limit: 5, IncludeTotal: true}
+	setupPaginatedGetResponder(t, "/pdb/query/v4/nodes", "", mockPaginatedGetOptions{
+		limit: pagination.Limit,
+		total: 10,
+		pageFilenames: []string{
+			"nodes-page-1-response.json",
+			"nodes-page-2-response.json",
+		},
+	})
+
+	cursor, err := pdbClient.PaginatedNodesCtx(context.Background(), "", &pagination, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	setupBlockingResponder("/pdb/query/v4/nodes")
+	actual, err := cursor.NextCtx(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, actual)
+	require.Equal(t, 1, cursor.CurrentPage())
+}
+
 func TestPaginatedNodes(t *testing.T) {
 	pagination := Pagination{
 		Limit:        5,
@@ -0,0 +1,119 @@
+package puppetdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	packages             = "/pdb/query/v4/packages"
+	packageInventory     = "/pdb/query/v4/package-inventory"
+	nodePackageInventory = "/pdb/query/v4/package-inventory/{certname}"
+)
+
+// Packages will return the distinct packages, by name, version and provider, installed on any node
+// matching the given query
+func (c *Client) Packages(query string, pagination *Pagination, orderBy *OrderBy) ([]Package, error) {
+	return c.PackagesCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PackagesCtx is Packages with a context that cancels the request when done
+func (c *Client) PackagesCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Package, error) {
+	payload := []Package{}
+	err := getRequest(ctx, c, packages, query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// PaginatedPackages works just like Packages, but returns a PackagesCursor that
+// provides methods for iterating over N pages of packages. If pagination is
+// nil, then a default configuration with a limit of 100 is used instead.
+func (c *Client) PaginatedPackages(query string, pagination *Pagination, orderBy *OrderBy) (*PackagesCursor, error) {
+	return c.PaginatedPackagesCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedPackagesCtx is PaginatedPackages with a context that cancels the request for the first page of packages,
+// use NextCtx to fetch the other pages with a context
+func (c *Client) PaginatedPackagesCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*PackagesCursor, error) {
+	cursor, err := newPageCursor[Package](ctx, c, packages, query, pagination, orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
+	}
+
+	return &PackagesCursor{Cursor: cursor}, nil
+}
+
+// PackageInventory will return the packages installed on each node matching the given query,
+// e.g. ["and", ["=", "package_name", "openssl"], ["~", "version", "^1\\."]] for the nodes with openssl 1.
+// Versions are strings, so < and > compare them as text rather than as versions.
+func (c *Client) PackageInventory(query string, pagination *Pagination, orderBy *OrderBy) ([]InstalledPackage, error) {
+	return c.PackageInventoryCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PackageInventoryCtx is PackageInventory with a context that cancels the request when done
+func (c *Client) PackageInventoryCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]InstalledPackage, error) {
+	payload := []InstalledPackage{}
+	err := getRequest(ctx, c, packageInventory, query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// NodePackageInventory will return the packages installed on a node by certname matching the given query
+func (c *Client) NodePackageInventory(certname, query string, pagination *Pagination, orderBy *OrderBy) ([]InstalledPackage, error) {
+	return c.NodePackageInventoryCtx(context.Background(), certname, query, pagination, orderBy)
+}
+
+// NodePackageInventoryCtx is NodePackageInventory with a context that cancels the request when done
+func (c *Client) NodePackageInventoryCtx(ctx context.Context, certname, query string, pagination *Pagination, orderBy *OrderBy) ([]InstalledPackage, error) {
+	payload := []InstalledPackage{}
+	path := strings.ReplaceAll(nodePackageInventory, "{certname}", url.PathEscape(certname))
+	err := getRequest(ctx, c, path, query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// PaginatedPackageInventory works just like PackageInventory, but returns a PackageInventoryCursor that
+// provides methods for iterating over N pages of installed packages. If pagination is
+// nil, then a default configuration with a limit of 100 is used instead.
+func (c *Client) PaginatedPackageInventory(query string, pagination *Pagination, orderBy *OrderBy) (*PackageInventoryCursor, error) {
+	return c.PaginatedPackageInventoryCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedPackageInventoryCtx is PaginatedPackageInventory with a context that cancels the request for the first
+// page of installed packages, use NextCtx to fetch the other pages with a context
+func (c *Client) PaginatedPackageInventoryCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*PackageInventoryCursor, error) {
+	cursor, err := newPageCursor[InstalledPackage](ctx, c, packageInventory, query, pagination, orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
+	}
+
+	return &PackageInventoryCursor{Cursor: cursor}, nil
+}
+
+// Package is a package known to PuppetDB. Provider is the package manager, such as yum or apt.
+type Package struct {
+	PackageName string `json:"package_name"`
+	Version     string `json:"version"`
+	Provider    string `json:"provider"`
+	Count       int    `json:"count,omitempty"`
+}
+
+// InstalledPackage is a package installed on the node Certname
+type InstalledPackage struct {
+	Certname    string `json:"certname"`
+	PackageName string `json:"package_name"`
+	Version     string `json:"version"`
+	Provider    string `json:"provider"`
+	Count       int    `json:"count,omitempty"`
+}
+
+// PackagesCursor is a pagination cursor that provides convenience methods for
+// stepping through pages of packages.
+type PackagesCursor struct {
+	*Cursor[Package]
+}
+
+// PackageInventoryCursor is a pagination cursor that provides convenience methods for
+// stepping through pages of installed packages.
+type PackageInventoryCursor struct {
+	*Cursor[InstalledPackage]
+}
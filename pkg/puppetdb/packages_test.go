@@ -0,0 +1,80 @@
+package puppetdb
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackages(t *testing.T) {
+	query := `["=", "package_name", "openssl"]`
+	setupGetResponder(t, packages, "query="+query, "packages-response.json")
+	actual, err := pdbClient.Packages(query, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedPackages, actual)
+}
+
+func TestPaginatedPackages(t *testing.T) {
+	pagination := Pagination{Limit: 2, IncludeTotal: true}
+	setupPaginatedGetResponder(t, packages, "", mockPaginatedGetOptions{
+		limit:         pagination.Limit,
+		total:         4,
+		pageFilenames: []string{"packages-response.json", "packages-response.json"},
+	})
+
+	cursor, err := pdbClient.PaginatedPackages("", &pagination, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, cursor.TotalPages())
+
+	actual, err := cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, expectedPackages, actual)
+
+	_, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestPackageInventory(t *testing.T) {
+	query := `["=", "package_name", "openssl"]`
+	setupGetResponder(t, packageInventory, "query="+query, "package-inventory-response.json")
+	actual, err := pdbClient.PackageInventory(query, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedPackageInventory, actual)
+
+	setupGetResponder(t, packageInventory+"/lenient-veranda.delivery.puppetlabs.net", "", "package-inventory-response.json")
+	actual, err = pdbClient.NodePackageInventory("lenient-veranda.delivery.puppetlabs.net", "", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedPackageInventory, actual)
+}
+
+func TestPaginatedPackageInventory(t *testing.T) {
+	pagination := Pagination{Limit: 2, IncludeTotal: true}
+	setupPaginatedGetResponder(t, packageInventory, "", mockPaginatedGetOptions{
+		limit:         pagination.Limit,
+		total:         4,
+		pageFilenames: []string{"package-inventory-response.json", "package-inventory-response.json"},
+	})
+
+	cursor, err := pdbClient.PaginatedPackageInventory("", &pagination, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, cursor.TotalPages())
+
+	actual, err := cursor.Next()
+	require.NoError(t, err)
+	require.Equal(t, expectedPackageInventory, actual)
+
+	actual, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+	require.Len(t, actual, 2)
+}
+
+var expectedPackages = []Package{
+	{PackageName: "openssl", Version: "1:1.0.2k-19.el7", Provider: "yum"},
+	{PackageName: "openssl", Version: "3.0.2-0ubuntu1.10", Provider: "apt"},
+}
+
+var expectedPackageInventory = []InstalledPackage{
+	{Certname: "lenient-veranda.delivery.puppetlabs.net", PackageName: "openssl", Version: "1:1.0.2k-19.el7", Provider: "yum"},
+	{Certname: "inland-ancestor.delivery.puppetlabs.net", PackageName: "openssl", Version: "3.0.2-0ubuntu1.10", Provider: "apt"},
+}
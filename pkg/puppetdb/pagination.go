@@ -1,9 +1,7 @@
 package puppetdb
 
 import (
-	"fmt"
-	"io"
-	"math"
+	"context"
 	"strconv"
 )
 
@@ -38,98 +36,13 @@ func NewDefaultPagination() *Pagination {
 	}
 }
 
-// pageCursor is a pagination cursor that provides convenience methods for
-// stepping through pbd response pages.
-type pageCursor struct {
-	client      *Client
-	path        string
-	query       string
-	pagination  *Pagination
-	orderBy     *OrderBy
-	currentPage any
-}
-
-// next makes a request for a page of results and iterates the pagination
-// cursor by the offset. If there are no more results left, the error will be
-// io.EOF.
-func (pc *pageCursor) next(response any) error {
-	// this block increases the offset and checks of it's greater than or equal
-	// to the total only if we have already returned a first page.
-
-	// make a copy of the paginator so we can calculate a new offset to use for
-	// the request and only "commit" it to the real pagination field if we
-	// don't recieve an error.
-	pg := *pc.pagination
-	if pc.currentPage != nil {
-		pg.Offset = pc.pagination.Offset + pc.pagination.Limit
-
-		if pg.Offset >= pc.pagination.Total {
-			return io.EOF
-		}
-	}
-
-	var err error
-
-	err = getRequest(pc.client, pc.path, pc.query, &pg, pc.orderBy, response)
-	if err != nil {
-		return fmt.Errorf("page cursor: client call returned an error: %w", err)
-	}
-
-	// if we don't recieve an error when executing the request, copy the new
-	// offset we calculated above to the real pagination object.
-	pc.pagination.Offset = pg.Offset
-	pc.currentPage = response
-
-	if pc.CurrentPage() == pc.TotalPages() {
-		err = io.EOF
-	}
-
-	return err
-}
-
-// TotalPages returns the total number of pages that can returns nodes.
-func (pc *pageCursor) TotalPages() int {
-	pagesf := float64(pc.pagination.Total) / float64(pc.pagination.Limit)
-	pages := int(math.Ceil(pagesf))
-
-	return pages
-}
-
-// CurrentPage returns the current page number the cursor is at.
-func (pc *pageCursor) CurrentPage() int {
-	if pc.pagination.Offset == 0 {
-		return 1
-	}
-
-	return pc.pagination.Offset/pc.pagination.Limit + 1
-}
-
-func newPageCursor(c *Client, path, query string, p *Pagination, orderBy *OrderBy) (*pageCursor, error) {
-	if p == nil {
-		p = NewDefaultPagination()
+// newPageCursor returns the cursor of a Paginated method, which fetches the first page with ctx so that
+// TotalPages is known at once, and returns io.EOF together with the last page
+func newPageCursor[T any](ctx context.Context, c *Client, path, query string, p *Pagination, orderBy *OrderBy) (*Cursor[T], error) {
+	cursor := NewCursor[T](c, path, query, p, orderBy, nil)
+	cursor.lastPageEOF = true
+	if err := cursor.fetchFirst(ctx); err != nil {
+		return nil, err
 	}
-
-	tempPagination := Pagination{
-		Limit:        1,
-		IncludeTotal: true,
-	}
-
-	// make a call to pdb for 1 object to fetch the total number of results for
-	// page calculations in the cursor.
-	if err := getRequest(c, path, query, &tempPagination, orderBy, &[]any{}); err != nil {
-		return nil, fmt.Errorf("failed to get result total from pdb: %w", err)
-	}
-
-	p.Total = tempPagination.Total
-	p.IncludeTotal = true
-
-	pc := &pageCursor{
-		client:     c,
-		path:       path,
-		query:      query,
-		pagination: p,
-		orderBy:    orderBy,
-	}
-
-	return pc, nil
+	return cursor, nil
 }
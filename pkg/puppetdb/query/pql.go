@@ -0,0 +1,744 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// SyntaxError is returned when a query cannot be parsed, Line and Column are where the error was found
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("pql: line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+// Parse parses a query in either syntax the root endpoint accepts, an AST if it starts with [ and PQL otherwise
+func Parse(query string) (Query, error) {
+	if strings.HasPrefix(strings.TrimSpace(query), "[") {
+		return ParseAST(query)
+	}
+	return ParsePQL(query)
+}
+
+// ParseAST parses a query in the AST syntax, returning a SyntaxError if it is not a JSON array
+func ParseAST(ast string) (Query, error) {
+	d := json.NewDecoder(strings.NewReader(ast))
+	d.UseNumber()
+	var q Query
+	if err := d.Decode(&q); err != nil {
+		line, column := position(ast, int(d.InputOffset()))
+		if se, ok := err.(*json.SyntaxError); ok {
+			// The offset is just after the character that is wrong
+			line, column = position(ast, int(se.Offset)-1)
+		}
+		return nil, &SyntaxError{Line: line, Column: column, Msg: err.Error()}
+	}
+	if d.More() {
+		line, column := position(ast, int(d.InputOffset()))
+		return nil, &SyntaxError{Line: line, Column: column, Msg: "unexpected text after the query"}
+	}
+	return q, nil
+}
+
+// position returns the line and column of the byte at offset in s
+func position(s string, offset int) (int, int) {
+	if offset > len(s) {
+		offset = len(s)
+	}
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	return line, len([]rune(before[strings.LastIndex(before, "\n")+1:])) + 1
+}
+
+// ParsePQL parses a PQL query such as nodes[certname] { facts_environment = "production" } into the AST
+// query for the root endpoint, a From query
+func ParsePQL(pql string) (Query, error) {
+	p, err := newParser(pql)
+	if err != nil {
+		return nil, err
+	}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	return q, p.expectEnd()
+}
+
+// ParsePQLFilter parses the filter of a PQL query, the part between the braces, into the AST query the
+// entity endpoints such as nodes take, e.g. certname ~ "^web" and facts_environment = "production"
+func ParsePQLFilter(pql string) (Query, error) {
+	p, err := newParser(pql)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+	q, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return q, p.expectEnd()
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+	tokenOperator
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	value  interface{}
+	line   int
+	column int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "the end of the query"
+	}
+	return strconv.Quote(t.text)
+}
+
+// lex splits pql into tokens, ending with a tokenEOF
+func lex(pql string) ([]token, error) {
+	var tokens []token
+	runes := []rune(pql)
+	line, column := 1, 1
+	i := 0
+	advance := func(n int) {
+		for ; n > 0; n-- {
+			if runes[i] == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+			i++
+		}
+	}
+
+	for {
+		for i < len(runes) && unicode.IsSpace(runes[i]) {
+			advance(1)
+		}
+		if i == len(runes) {
+			return append(tokens, token{kind: tokenEOF, line: line, column: column}), nil
+		}
+
+		t := token{line: line, column: column}
+		r := runes[i]
+		start := i
+		switch {
+		case strings.ContainsRune("[]{}(),", r):
+			t.kind = tokenPunct
+			advance(1)
+		case r == '"' || r == '\'':
+			value, n, err := lexString(runes[i:])
+			if err != nil {
+				return nil, &SyntaxError{Line: line, Column: column, Msg: err.Error()}
+			}
+			t.kind, t.value = tokenString, value
+			advance(n)
+		case r == '-' || unicode.IsDigit(r):
+			n := 1
+			for i+n < len(runes) && (unicode.IsDigit(runes[i+n]) || strings.ContainsRune(".eE+-", runes[i+n])) {
+				n++
+			}
+			value, err := parseNumber(string(runes[i : i+n]))
+			if err != nil {
+				return nil, &SyntaxError{Line: line, Column: column, Msg: fmt.Sprintf("invalid number %q", string(runes[i:i+n]))}
+			}
+			t.kind, t.value = tokenNumber, value
+			advance(n)
+		case isIdentStart(r):
+			n := 1
+			for i+n < len(runes) && isIdentPart(runes[i+n]) {
+				n++
+			}
+			t.kind = tokenIdent
+			advance(n)
+		default:
+			n := 1
+			if i+1 < len(runes) {
+				switch string(runes[i : i+2]) {
+				case "!=", "!~", ">=", "<=", "~>":
+					n = 2
+				}
+			}
+			if n == 1 && !strings.ContainsRune("=<>~!", r) {
+				return nil, &SyntaxError{Line: line, Column: column, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			t.kind = tokenOperator
+			advance(n)
+		}
+		t.text = string(runes[start:i])
+		tokens = append(tokens, t)
+	}
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == '?'
+}
+
+// isIdent reports whether s lexes as a single identifier, such as an entity or field name
+func isIdent(s string) bool {
+	for i, r := range s {
+		if (i == 0 && !isIdentStart(r)) || !isIdentPart(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// lexString reads the quoted string at the start of runes, returning its value and length
+func lexString(runes []rune) (string, int, error) {
+	quote := runes[0]
+	var b strings.Builder
+	for i := 1; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case r == quote:
+			return b.String(), i + 1, nil
+		case r == '\\' && i+1 < len(runes):
+			i++
+			switch e := runes[i]; e {
+			case 'n':
+				b.WriteRune('\n')
+			case 't':
+				b.WriteRune('\t')
+			case 'r':
+				b.WriteRune('\r')
+			case 'b':
+				b.WriteRune('\b')
+			case 'f':
+				b.WriteRune('\f')
+			case 'u':
+				u, n, err := lexUnicodeEscape(runes[i-1:])
+				if err != nil {
+					return "", 0, err
+				}
+				b.WriteRune(u)
+				i += n - 2
+			case '\\', '"', '\'':
+				b.WriteRune(e)
+			default:
+				// Keep other escapes, such as \d in a regular expression, as they are
+				b.WriteRune('\\')
+				b.WriteRune(e)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// lexUnicodeEscape reads the \uXXXX escape at the start of runes, and the low surrogate escape after it if it
+// is a high surrogate as the JSON encoder writes characters outside the basic multilingual plane. It returns
+// the character and the length of the escapes.
+func lexUnicodeEscape(runes []rune) (rune, int, error) {
+	r, ok := hexRune(runes)
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid unicode escape")
+	}
+	if utf16.IsSurrogate(r) {
+		if low, ok := hexRune(runes[6:]); ok {
+			if pair := utf16.DecodeRune(r, low); pair != unicode.ReplacementChar {
+				return pair, 12, nil
+			}
+		}
+		return unicode.ReplacementChar, 6, nil
+	}
+	return r, 6, nil
+}
+
+// hexRune returns the rune of the \uXXXX escape at the start of runes
+func hexRune(runes []rune) (rune, bool) {
+	if len(runes) < 6 || runes[0] != '\\' || runes[1] != 'u' {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(string(runes[2:6]), 16, 16)
+	if err != nil {
+		return 0, false
+	}
+	return rune(n), true
+}
+
+func parseNumber(s string) (interface{}, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func newParser(pql string) (*parser, error) {
+	tokens, err := lex(pql)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// peekAt returns the token n after the next one
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is reports whether t is the punctuation, operator or keyword text
+func (t token) is(text string) bool {
+	return t.kind != tokenEOF && t.kind != tokenString && t.kind != tokenNumber && t.text == text
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Line: t.line, Column: t.column, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) expect(text string) error {
+	if t := p.next(); !t.is(text) {
+		return p.errorf(t, "expected %q, found %s", text, t)
+	}
+	return nil
+}
+
+func (p *parser) expectIdent(what string) (string, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return "", p.errorf(t, "expected %s, found %s", what, t)
+	}
+	return t.text, nil
+}
+
+func (p *parser) expectEnd() error {
+	if t := p.peek(); t.kind != tokenEOF {
+		return p.errorf(t, "unexpected %s", t)
+	}
+	return nil
+}
+
+// parseQuery parses entity[projection] { filter modifiers }, the projection and braces being optional
+func (p *parser) parseQuery() (Query, error) {
+	entity, err := p.expectIdent("an entity such as nodes")
+	if err != nil {
+		return nil, err
+	}
+	var fields []interface{}
+	if p.peek().is("[") {
+		if fields, err = p.parseProjection(); err != nil {
+			return nil, err
+		}
+	}
+
+	var filter Query
+	var groupBy []string
+	var paging []Query
+	if p.peek().is("{") {
+		brace := p.next()
+		if !p.peek().is("}") && !p.atModifier() {
+			if filter, err = p.parseExpression(); err != nil {
+				return nil, err
+			}
+		}
+		for p.atModifier() {
+			t := p.next()
+			switch t.text {
+			case "group":
+				p.next()
+				if groupBy, err = p.parseFieldList(); err != nil {
+					return nil, err
+				}
+				if fields == nil {
+					return nil, p.errorf(t, "group by needs a projection such as %s[name, count()]", entity)
+				}
+			case "order":
+				p.next()
+				orderBy, err := p.parseOrderBy()
+				if err != nil {
+					return nil, err
+				}
+				paging = append(paging, Query{"order_by", orderBy})
+			default:
+				paging = append(paging, Query{t.text, p.next().value})
+			}
+		}
+		if t := p.next(); !t.is("}") {
+			return nil, p.errorf(t, "expected \"}\" to close the \"{\" at line %d, column %d, found %s", brace.line, brace.column, t)
+		}
+	}
+
+	q := Query{"from", entity}
+	switch {
+	case fields != nil:
+		extract := Extract(filter, fields...)
+		if groupBy != nil {
+			extract = extract.GroupBy(groupBy...)
+		}
+		q = append(q, extract)
+	case filter != nil:
+		q = append(q, filter)
+	}
+	for _, clause := range paging {
+		q = append(q, clause)
+	}
+	return q, nil
+}
+
+// atModifier reports whether the next tokens are limit n, offset n, order by or group by
+func (p *parser) atModifier() bool {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return false
+	}
+	switch t.text {
+	case "limit", "offset":
+		next := p.peekAt(1)
+		_, isInt := next.value.(int)
+		return next.kind == tokenNumber && isInt
+	case "order", "group":
+		return p.peekAt(1).is("by")
+	}
+	return false
+}
+
+// parseOrderBy parses field [asc|desc], ...
+func (p *parser) parseOrderBy() ([]interface{}, error) {
+	var orderBy []interface{}
+	for {
+		field, err := p.expectIdent("a field")
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t.is("asc") || t.is("desc") {
+			orderBy = append(orderBy, []interface{}{field, p.next().text})
+		} else {
+			orderBy = append(orderBy, field)
+		}
+		if !p.peek().is(",") {
+			return orderBy, nil
+		}
+		p.next()
+	}
+}
+
+// parseFieldList parses field, field, ...
+func (p *parser) parseFieldList() ([]string, error) {
+	var fields []string
+	for {
+		field, err := p.expectIdent("a field")
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		if !p.peek().is(",") {
+			return fields, nil
+		}
+		p.next()
+	}
+}
+
+// functions are the functions of a projection and how many arguments they take
+var functions = map[string][2]int{
+	"count":     {0, 1},
+	"avg":       {1, 1},
+	"sum":       {1, 1},
+	"min":       {1, 1},
+	"max":       {1, 1},
+	"to_string": {2, 2},
+}
+
+// parseProjection parses [field, function(args), ...]
+func (p *parser) parseProjection() ([]interface{}, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	var fields []interface{}
+	for {
+		t := p.next()
+		if t.kind != tokenIdent {
+			return nil, p.errorf(t, "expected a field or function, found %s", t)
+		}
+		if !p.peek().is("(") {
+			fields = append(fields, t.text)
+		} else {
+			arity, ok := functions[t.text]
+			if !ok {
+				return nil, p.errorf(t, "unknown function %q", t.text)
+			}
+			p.next()
+			function := Query{"function", t.text}
+			for !p.peek().is(")") {
+				if len(function) > 2 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				arg := p.next()
+				if arg.kind != tokenIdent && arg.kind != tokenString {
+					return nil, p.errorf(arg, "expected a function argument, found %s", arg)
+				}
+				if arg.kind == tokenString {
+					function = append(function, arg.value)
+				} else {
+					function = append(function, arg.text)
+				}
+			}
+			p.next()
+			if n := len(function) - 2; n < arity[0] || n > arity[1] {
+				return nil, p.errorf(t, "wrong number of arguments to %s", t.text)
+			}
+			fields = append(fields, function)
+		}
+
+		if t := p.next(); t.is("]") {
+			return fields, nil
+		} else if !t.is(",") {
+			return nil, p.errorf(t, "expected \",\" or \"]\", found %s", t)
+		}
+	}
+}
+
+// parseExpression parses a filter, or has the lowest precedence followed by and then !
+func (p *parser) parseExpression() (Query, error) {
+	return p.parseBoolean("or", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Query, error) {
+	return p.parseBoolean("and", p.parseNot)
+}
+
+func (p *parser) parseBoolean(op string, parseOperand func() (Query, error)) (Query, error) {
+	q, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek().is(op) {
+		return q, nil
+	}
+	queries := []Query{q}
+	for p.peek().is(op) {
+		p.next()
+		if q, err = parseOperand(); err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+	return operator(op, queries), nil
+}
+
+func (p *parser) parseNot() (Query, error) {
+	if p.peek().is("!") {
+		p.next()
+		q, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not(q), nil
+	}
+	return p.parseCondition()
+}
+
+// parseCondition parses a parenthesised expression, an implicit subquery or a condition on a field
+func (p *parser) parseCondition() (Query, error) {
+	t := p.peek()
+	switch {
+	case t.is("("):
+		p.next()
+		q, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); !closing.is(")") {
+			return nil, p.errorf(closing, "expected \")\" to close the \"(\" at line %d, column %d, found %s", t.line, t.column, closing)
+		}
+		return q, nil
+	case t.is("["):
+		p.next()
+		fields, err := p.parseFieldList()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("in"); err != nil {
+			return nil, err
+		}
+		subquery, err := p.parseInSubquery()
+		if err != nil {
+			return nil, err
+		}
+		return InFields(fields, subquery), nil
+	case t.kind != tokenIdent:
+		return nil, p.errorf(t, "expected a condition, found %s", t)
+	}
+
+	field := p.next().text
+	op := p.next()
+	switch {
+	case op.is("{"):
+		p.pos--
+		filter, err := p.parseBraces()
+		if err != nil {
+			return nil, err
+		}
+		return Subquery(field, filter), nil
+	case op.is("is"):
+		isNull := true
+		if p.peek().is("not") {
+			p.next()
+			isNull = false
+		}
+		if err := p.expect("null"); err != nil {
+			return nil, err
+		}
+		return Null(field, isNull), nil
+	case op.is("in"):
+		if p.peek().is("[") {
+			values, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			return InArray(field, values.([]interface{})...), nil
+		}
+		subquery, err := p.parseInSubquery()
+		if err != nil {
+			return nil, err
+		}
+		return In(field, subquery), nil
+	case op.kind == tokenOperator && !op.is("!"):
+		valueToken := p.peek()
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		switch op.text {
+		case "!=":
+			return Not(Equal(field, value)), nil
+		case "!~":
+			return Not(Query{"~", field, value}), nil
+		case "~>":
+			if _, ok := value.([]interface{}); !ok {
+				return nil, p.errorf(valueToken, "~> needs an array of regular expressions")
+			}
+		}
+		return Query{op.text, field, value}, nil
+	}
+	return nil, p.errorf(op, "expected an operator after %q, found %s", field, op)
+}
+
+// parseBraces parses { filter }, returning a nil filter for {}
+func (p *parser) parseBraces() (Query, error) {
+	brace := p.next()
+	if p.peek().is("}") {
+		p.next()
+		return nil, nil
+	}
+	q, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.next(); !t.is("}") {
+		return nil, p.errorf(t, "expected \"}\" to close the \"{\" at line %d, column %d, found %s", brace.line, brace.column, t)
+	}
+	return q, nil
+}
+
+// parseInSubquery parses entity[projection] { filter }, the subquery of in
+func (p *parser) parseInSubquery() (Query, error) {
+	entity, err := p.expectIdent("an entity such as facts")
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); !t.is("[") {
+		return nil, p.errorf(t, "expected a projection such as %s[certname], found %s", entity, t)
+	}
+	fields, err := p.parseProjection()
+	if err != nil {
+		return nil, err
+	}
+	var filter Query
+	if p.peek().is("{") {
+		if filter, err = p.parseBraces(); err != nil {
+			return nil, err
+		}
+	}
+	return Extract(Select(entity, filter), fields...), nil
+}
+
+// parseValue parses a string, number, boolean or array of them
+func (p *parser) parseValue() (interface{}, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenString || t.kind == tokenNumber:
+		return t.value, nil
+	case t.is("true"), t.is("false"):
+		return t.text == "true", nil
+	case t.is("["):
+		values := []interface{}{}
+		for !p.peek().is("]") {
+			if len(values) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		p.next()
+		return values, nil
+	}
+	return nil, p.errorf(t, "expected a value, found %s", t)
+}
+
+// encode returns v as JSON without escaping HTML characters
+func encode(v interface{}) (string, error) {
+	var b bytes.Buffer
+	e := json.NewEncoder(&b)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSuffix(b.Bytes(), []byte("\n"))), nil
+}
@@ -0,0 +1,186 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pqlTests are PQL queries, as the printer writes them, and their AST
+var pqlTests = []struct {
+	pql string
+	ast string
+}{
+	{`nodes {}`, `["from","nodes"]`},
+	{`nodes { certname = "node1.example.com" }`, `["from","nodes",["=","certname","node1.example.com"]]`},
+	{`nodes[certname, report_timestamp] {}`, `["from","nodes",["extract",["certname","report_timestamp"]]]`},
+	{`nodes[certname] { facts_environment = "production" and (certname ~ "^web\\d+" or certname ~ "^db") }`,
+		`["from","nodes",["extract",["certname"],["and",["=","facts_environment","production"],["or",["~","certname","^web\\d+"],["~","certname","^db"]]]]]`},
+	{`nodes { certname ~ "^web" or facts_environment = "production" and deactivated is null }`,
+		`["from","nodes",["or",["~","certname","^web"],["and",["=","facts_environment","production"],["null?","deactivated",true]]]]`},
+	{`nodes { deactivated is not null and !(expired is null) }`,
+		`["from","nodes",["and",["null?","deactivated",false],["not",["null?","expired",true]]]]`},
+	{`nodes { certname != "a" and certname !~ "b" and !(certname = "c" or certname = "d") }`,
+		`["from","nodes",["and",["not",["=","certname","a"]],["not",["~","certname","b"]],["not",["or",["=","certname","c"],["=","certname","d"]]]]]`},
+	{`facts { value > 1 and value >= 2.5 and value < -3 and value <= 4 and value = true }`,
+		`["from","facts",["and",[">","value",1],[">=","value",2.5],["<","value",-3],["<=","value",4],["=","value",true]]]`},
+	{`fact_contents { path ~> ["networking", "interfaces", ".*"] and path = ["os", "family"] }`,
+		`["from","fact_contents",["and",["~>","path",["networking","interfaces",".*"]],["=","path",["os","family"]]]]`},
+	{`nodes { certname in facts[certname] { name = "osfamily" and value = "RedHat" } }`,
+		`["from","nodes",["in","certname",["extract",["certname"],["select_facts",["and",["=","name","osfamily"],["=","value","RedHat"]]]]]]`},
+	{`resources { [certname, title] in resources[certname, title] }`,
+		`["from","resources",["in",["certname","title"],["extract",["certname","title"],["select_resources"]]]]`},
+	{`nodes { certname in ["node1", "node2"] }`, `["from","nodes",["in","certname",["array",["node1","node2"]]]]`},
+	{`nodes { facts { name = "osfamily" } and resources {} }`,
+		`["from","nodes",["and",["subquery","facts",["=","name","osfamily"]],["subquery","resources"]]]`},
+	{`facts[name, count(), avg(value)] { group by name }`,
+		`["from","facts",["extract",["name",["function","count"],["function","avg","value"]],["group_by","name"]]]`},
+	{`reports[certname, to_string(producer_timestamp, "FMDAY"), count(certname)] { status = "failed" group by certname, producer_timestamp }`,
+		`["from","reports",["extract",["certname",["function","to_string","producer_timestamp","FMDAY"],["function","count","certname"]],["=","status","failed"],["group_by","certname","producer_timestamp"]]]`},
+	{`nodes { certname ~ "web" limit 10 offset 20 order by certname desc, report_timestamp }`,
+		`["from","nodes",["~","certname","web"],["limit",10],["offset",20],["order_by",[["certname","desc"],"report_timestamp"]]]`},
+	{`nodes { limit 1 }`, `["from","nodes",["limit",1]]`},
+	{`inventory[certname] { facts.os.family = "RedHat" and trusted.extensions.pp_role = "a \"quoted\" <role>" }`,
+		`["from","inventory",["extract",["certname"],["and",["=","facts.os.family","RedHat"],["=","trusted.extensions.pp_role","a \"quoted\" <role>"]]]]`},
+}
+
+func TestParsePQL(t *testing.T) {
+	for _, test := range pqlTests {
+		q, err := ParsePQL(test.pql)
+		require.Nil(t, err, test.pql)
+		require.Equal(t, test.ast, q.String(), test.pql)
+	}
+
+	// Test optional braces, whitespace and escapes
+	q, err := ParsePQL("nodes[certname]")
+	require.Nil(t, err)
+	require.Equal(t, `["from","nodes",["extract",["certname"]]]`, q.String())
+	q, err = ParsePQL("\n  nodes  {\n\tcertname='it\\'s'\n}\n")
+	require.Nil(t, err)
+	require.Equal(t, `["from","nodes",["=","certname","it's"]]`, q.String())
+}
+
+func TestPQL(t *testing.T) {
+	for _, test := range pqlTests {
+		q, err := ParseAST(test.ast)
+		require.Nil(t, err, test.ast)
+		pql, err := q.PQL()
+		require.Nil(t, err, test.ast)
+		require.Equal(t, test.pql, pql)
+	}
+
+	// Test queries from the builder
+	pql, err := Or(Equal("certname", "a"), And(Equal("b", 1), Not(And(Equal("c", 2), Equal("d", 3))))).PQL()
+	require.Nil(t, err)
+	require.Equal(t, `certname = "a" or b = 1 and !(c = 2 and d = 3)`, pql)
+	pql, err = From(EntityNodes, In("certname", From(EntityFacts, Extract(Equal("name", "a"), "certname")))).PQL()
+	require.Nil(t, err)
+	require.Equal(t, `nodes { certname in facts[certname] { name = "a" } }`, pql)
+	pql, err = Query(nil).PQL()
+	require.Nil(t, err)
+	require.Equal(t, "", pql)
+
+	// Test values with escapes parse back to the same query
+	for _, value := range []string{"a\x01b", "a\bb\fc", "line\u2028para\u2029", "\U0001F600", `"quoted" \d+`} {
+		q := Equal("certname", value)
+		pql, err = q.PQL()
+		require.Nil(t, err, value)
+		parsed, err := ParsePQLFilter(pql)
+		require.Nil(t, err, pql)
+		require.Equal(t, q.String(), parsed.String(), pql)
+	}
+	q, err := ParsePQLFilter(`certname = "\ud83d\ude00 \u00e9"`)
+	require.Nil(t, err)
+	require.Equal(t, Equal("certname", "\U0001F600 \u00e9").String(), q.String())
+
+	// Test queries PQL cannot express
+	for _, q := range []Query{
+		{"and"},
+		{"unknown", "certname", "a"},
+		{"=", "certname"},
+		{"null?", "certname", "yes"},
+		{"in", "certname", Query{"extract", []string{"certname"}, Query{"nodes"}}},
+		Equal("certname", nil),
+		{"from"},
+		{"from", "nodes", Query{"limit"}},
+		{"from", "nodes", Extract(nil, Query{"count"})},
+		{"from", ""},
+		{"from", "nodes", Query{"=", "a b", 1}},
+		{"=", "1certname", "a"},
+		{"null?", "", true},
+		{"in", []string{"certname", "report timestamp"}, Query{"array", []string{"a"}}},
+		{"subquery", "fact contents"},
+		{"from", "nodes", Extract(nil, Query{"function", "median", "value"})},
+		{"from", "nodes", Query{"extract", []string{"certname"}, Query{"group_by", "a,b"}}},
+		{"from", "nodes", Query{"order_by", []interface{}{[]string{"certname", "up"}}}},
+	} {
+		pql, err := q.PQL()
+		require.NotNil(t, err, q.String())
+		require.Empty(t, pql)
+	}
+	_, err = Query{"=", "certname", map[string]interface{}{"a": 1}}.PQL()
+	require.EqualError(t, err, `pql: {"a":1} cannot be written in PQL`)
+}
+
+func TestParsePQLFilter(t *testing.T) {
+	q, err := ParsePQLFilter(`certname ~ "^web" and facts_environment = "production"`)
+	require.Nil(t, err)
+	require.Equal(t, And(Regex("certname", "^web"), Equal("facts_environment", "production")).String(), q.String())
+
+	q, err = ParsePQLFilter("  ")
+	require.Nil(t, err)
+	require.Nil(t, q)
+
+	_, err = ParsePQLFilter(`certname = "a" limit 10`)
+	require.EqualError(t, err, `pql: line 1, column 16: unexpected "limit"`)
+}
+
+func TestPQLSyntaxErrors(t *testing.T) {
+	for pql, expected := range map[string]string{
+		``:                                      `pql: line 1, column 1: expected an entity such as nodes, found the end of the query`,
+		`nodes { certname = }`:                  `pql: line 1, column 20: expected a value, found "}"`,
+		"nodes {\n  certname = \"a\"\n  and\n}": `pql: line 4, column 1: expected a condition, found "}"`,
+		`nodes { certname = "a"`:                `pql: line 1, column 23: expected "}" to close the "{" at line 1, column 7, found the end of the query`,
+		`nodes { (certname = "a" }`:             `pql: line 1, column 25: expected ")" to close the "(" at line 1, column 9, found "}"`,
+		`nodes { certname "a" }`:                `pql: line 1, column 18: expected an operator after "certname", found "\"a\""`,
+		`nodes { certname = "a }`:               `pql: line 1, column 20: unterminated string`,
+		`nodes { certname = "\u12" }`:           `pql: line 1, column 20: invalid unicode escape`,
+		`nodes { certname = 1.2.3 }`:            `pql: line 1, column 20: invalid number "1.2.3"`,
+		`nodes { certname # "a" }`:              `pql: line 1, column 18: unexpected character '#'`,
+		`nodes { certname in facts }`:           `pql: line 1, column 27: expected a projection such as facts[certname], found "}"`,
+		`nodes { path ~> "a" }`:                 `pql: line 1, column 17: ~> needs an array of regular expressions`,
+		`nodes { deactivated is true }`:         `pql: line 1, column 24: expected "null", found "true"`,
+		`nodes[median(value)] {}`:               `pql: line 1, column 7: unknown function "median"`,
+		`nodes[count(a, b)] {}`:                 `pql: line 1, column 7: wrong number of arguments to count`,
+		`nodes[certname {}`:                     `pql: line 1, column 16: expected "," or "]", found "{"`,
+		`nodes { group by certname }`:           `pql: line 1, column 9: group by needs a projection such as nodes[name, count()]`,
+		`nodes {} facts {}`:                     `pql: line 1, column 10: unexpected "facts"`,
+	} {
+		_, err := ParsePQL(pql)
+		require.EqualError(t, err, expected, pql)
+		require.IsType(t, &SyntaxError{}, err)
+	}
+}
+
+func TestParseAST(t *testing.T) {
+	q, err := ParseAST(`["=", "value", 10000000000000001]`)
+	require.Nil(t, err)
+	require.Equal(t, `["=","value",10000000000000001]`, q.String())
+
+	_, err = ParseAST("[\"=\",\n \"certname\" \"a\"]")
+	require.EqualError(t, err, "pql: line 2, column 13: invalid character '\"' after array element")
+	_, err = ParseAST(`["=", "certname", "a"] []`)
+	require.EqualError(t, err, "pql: line 1, column 24: unexpected text after the query")
+	_, err = ParseAST(`{"certname": "a"}`)
+	require.NotNil(t, err)
+}
+
+func TestParse(t *testing.T) {
+	q, err := Parse(` ["from", "nodes"]`)
+	require.Nil(t, err)
+	require.Equal(t, `["from","nodes"]`, q.String())
+
+	q, err = Parse(`nodes {}`)
+	require.Nil(t, err)
+	require.Equal(t, `["from","nodes"]`, q.String())
+}
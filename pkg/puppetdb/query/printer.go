@@ -0,0 +1,434 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// PQL returns the query in PQL. A From query, such as one returned by ParsePQL, is printed as a full PQL
+// query and any other query as the filter of one, the form ParsePQLFilter parses.
+func (q Query) PQL() (string, error) {
+	if q == nil {
+		return "", nil
+	}
+	var b strings.Builder
+	var err error
+	if op, _ := q.op(); op == "from" {
+		err = printFrom(&b, q)
+	} else {
+		err = printExpression(&b, q, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// op returns the operator of the query or expression, its first element
+func (q Query) op() (string, bool) {
+	if len(q) == 0 {
+		return "", false
+	}
+	op, ok := q[0].(string)
+	return op, ok
+}
+
+// asQuery returns v as a Query if it is an array, such as a nested Query or one decoded by ParseAST
+func asQuery(v interface{}) (Query, bool) {
+	switch v := v.(type) {
+	case Query:
+		return v, true
+	case []interface{}:
+		return v, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	q := make(Query, rv.Len())
+	for i := range q {
+		q[i] = rv.Index(i).Interface()
+	}
+	return q, true
+}
+
+func unsupported(v interface{}) error {
+	s, err := encode(v)
+	if err != nil {
+		s = fmt.Sprint(v)
+	}
+	return fmt.Errorf("pql: %s cannot be written in PQL", s)
+}
+
+// printName prints an entity, field or function name, which PQL writes as an identifier
+func printName(b *strings.Builder, v interface{}) error {
+	name, ok := v.(string)
+	if !ok || !isIdent(name) {
+		return unsupported(v)
+	}
+	b.WriteString(name)
+	return nil
+}
+
+// printFrom prints ["from", entity, query or extract, paging...]
+func printFrom(b *strings.Builder, q Query) error {
+	if len(q) < 2 {
+		return unsupported(q)
+	}
+	if err := printName(b, q[1]); err != nil {
+		return err
+	}
+
+	var parts []string
+	rest := q[2:]
+	if len(rest) > 0 {
+		first, _ := asQuery(rest[0])
+		switch op, _ := first.op(); op {
+		case "extract":
+			filter, groupBy, err := printExtract(b, first)
+			if err != nil {
+				return err
+			}
+			if filter != "" {
+				parts = append(parts, filter)
+			}
+			if groupBy != "" {
+				parts = append(parts, groupBy)
+			}
+			rest = rest[1:]
+		case "limit", "offset", "order_by":
+		default:
+			var filter strings.Builder
+			if err := printExpression(&filter, rest[0], ""); err != nil {
+				return err
+			}
+			parts = append(parts, filter.String())
+			rest = rest[1:]
+		}
+	}
+
+	for _, v := range rest {
+		clause, ok := asQuery(v)
+		if !ok || len(clause) != 2 {
+			return unsupported(v)
+		}
+		switch op, _ := clause.op(); op {
+		case "limit", "offset":
+			parts = append(parts, fmt.Sprintf("%s %v", op, clause[1]))
+		case "order_by":
+			orderBy, err := printOrderBy(clause[1])
+			if err != nil {
+				return err
+			}
+			parts = append(parts, orderBy)
+		default:
+			return unsupported(v)
+		}
+	}
+
+	if len(parts) == 0 {
+		b.WriteString(" {}")
+	} else {
+		b.WriteString(" { " + strings.Join(parts, " ") + " }")
+	}
+	return nil
+}
+
+// printExtract prints the projection of ["extract", fields, filter, group_by], returning the filter and group by
+func printExtract(b *strings.Builder, extract Query) (string, string, error) {
+	if len(extract) < 2 {
+		return "", "", unsupported(extract)
+	}
+	if err := printProjection(b, extract[1]); err != nil {
+		return "", "", err
+	}
+
+	var filter, groupBy strings.Builder
+	for _, v := range extract[2:] {
+		q, _ := asQuery(v)
+		if op, _ := q.op(); op == "group_by" {
+			groupBy.WriteString("group by ")
+			for i, field := range q[1:] {
+				if i > 0 {
+					groupBy.WriteString(", ")
+				}
+				if err := printName(&groupBy, field); err != nil {
+					return "", "", err
+				}
+			}
+			continue
+		}
+		if filter.Len() > 0 {
+			return "", "", unsupported(extract)
+		}
+		if err := printExpression(&filter, v, ""); err != nil {
+			return "", "", err
+		}
+	}
+	return filter.String(), groupBy.String(), nil
+}
+
+// printProjection prints [field, function(args), ...]
+func printProjection(b *strings.Builder, fields interface{}) error {
+	list, ok := asQuery(fields)
+	if !ok {
+		// A single field may be given without an array
+		list = Query{fields}
+	}
+	b.WriteString("[")
+	for i, field := range list {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if _, ok := field.(string); ok {
+			if err := printName(b, field); err != nil {
+				return err
+			}
+			continue
+		}
+		function, ok := asQuery(field)
+		if op, _ := function.op(); !ok || op != "function" || len(function) < 2 {
+			return unsupported(field)
+		}
+		// The parser only knows the functions and how many arguments they take
+		name, _ := function[1].(string)
+		if arity, ok := functions[name]; !ok || len(function)-2 < arity[0] || len(function)-2 > arity[1] {
+			return unsupported(field)
+		}
+		b.WriteString(name + "(")
+		for j, arg := range function[2:] {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			var err error
+			if j == 0 {
+				err = printName(b, arg)
+			} else {
+				err = printValue(b, arg)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		b.WriteString(")")
+	}
+	b.WriteString("]")
+	return nil
+}
+
+// printOrderBy prints order by field [asc|desc], ...
+func printOrderBy(v interface{}) (string, error) {
+	list, ok := asQuery(v)
+	if !ok || len(list) == 0 {
+		return "", unsupported(v)
+	}
+	var b strings.Builder
+	b.WriteString("order by ")
+	for i, field := range list {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		pair, ok := asQuery(field)
+		if !ok {
+			pair = Query{field}
+		} else if len(pair) != 2 || (pair[1] != "asc" && pair[1] != "desc") {
+			return "", unsupported(v)
+		}
+		if err := printName(&b, pair[0]); err != nil {
+			return "", err
+		}
+		if len(pair) == 2 {
+			b.WriteString(" " + pair[1].(string))
+		}
+	}
+	return b.String(), nil
+}
+
+// printExpression prints a filter, parent is the operator of the expression it is within
+func printExpression(b *strings.Builder, v interface{}, parent string) error {
+	q, ok := asQuery(v)
+	op, isOp := q.op()
+	if !ok || !isOp {
+		return unsupported(v)
+	}
+
+	switch op {
+	case "and", "or":
+		if len(q) < 2 {
+			return unsupported(q)
+		}
+		if len(q) == 2 {
+			return printExpression(b, q[1], parent)
+		}
+		// and binds more tightly than or, so only an or within an and, or either within a not, needs parentheses
+		parens := (op == "or" && parent == "and") || parent == "not"
+		if parens {
+			b.WriteString("(")
+		}
+		for i, operand := range q[1:] {
+			if i > 0 {
+				b.WriteString(" " + op + " ")
+			}
+			if err := printExpression(b, operand, op); err != nil {
+				return err
+			}
+		}
+		if parens {
+			b.WriteString(")")
+		}
+		return nil
+
+	case "not":
+		if len(q) != 2 {
+			return unsupported(q)
+		}
+		inner, _ := asQuery(q[1])
+		if innerOp, _ := inner.op(); len(inner) == 3 && (innerOp == "=" || innerOp == "~") {
+			return printCondition(b, inner[1], "!"+innerOp, inner[2])
+		}
+		b.WriteString("!")
+		if innerOp, _ := inner.op(); innerOp != "and" && innerOp != "or" && innerOp != "not" {
+			b.WriteString("(")
+			defer b.WriteString(")")
+		}
+		return printExpression(b, q[1], "not")
+
+	case "=", ">", ">=", "<", "<=", "~", "~>":
+		if len(q) != 3 {
+			return unsupported(q)
+		}
+		return printCondition(b, q[1], op, q[2])
+
+	case "null?":
+		if len(q) != 3 {
+			return unsupported(q)
+		}
+		isNull, ok := q[2].(bool)
+		if !ok {
+			return unsupported(q)
+		}
+		if err := printName(b, q[1]); err != nil {
+			return err
+		}
+		if isNull {
+			b.WriteString(" is null")
+		} else {
+			b.WriteString(" is not null")
+		}
+		return nil
+
+	case "in":
+		if len(q) != 3 {
+			return unsupported(q)
+		}
+		if fields, ok := asQuery(q[1]); ok {
+			b.WriteString("[")
+			for i, field := range fields {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				if err := printName(b, field); err != nil {
+					return err
+				}
+			}
+			b.WriteString("]")
+		} else if err := printName(b, q[1]); err != nil {
+			return err
+		}
+		b.WriteString(" in ")
+		return printInSubquery(b, q[2])
+
+	case "subquery":
+		if len(q) < 2 || len(q) > 3 {
+			return unsupported(q)
+		}
+		if err := printName(b, q[1]); err != nil {
+			return err
+		}
+		if len(q) == 2 {
+			b.WriteString(" {}")
+			return nil
+		}
+		b.WriteString(" { ")
+		if err := printExpression(b, q[2], ""); err != nil {
+			return err
+		}
+		b.WriteString(" }")
+		return nil
+	}
+	return unsupported(q)
+}
+
+func printCondition(b *strings.Builder, field interface{}, op string, value interface{}) error {
+	if err := printName(b, field); err != nil {
+		return err
+	}
+	b.WriteString(" " + op + " ")
+	return printValue(b, value)
+}
+
+// printInSubquery prints the subquery of in, an array, ["extract", fields, ["select_entity", filter]] or
+// ["from", entity, ["extract", fields, filter]]
+func printInSubquery(b *strings.Builder, v interface{}) error {
+	q, _ := asQuery(v)
+	op, _ := q.op()
+	switch {
+	case op == "array" && len(q) == 2:
+		return printValue(b, q[1])
+	case op == "extract" && len(q) == 3:
+		selectQuery, _ := asQuery(q[2])
+		selectOp, _ := selectQuery.op()
+		if !strings.HasPrefix(selectOp, "select_") || len(selectQuery) > 2 {
+			return unsupported(v)
+		}
+		if err := printName(b, strings.TrimPrefix(selectOp, "select_")); err != nil {
+			return err
+		}
+		if err := printProjection(b, q[1]); err != nil {
+			return err
+		}
+		if len(selectQuery) == 2 {
+			b.WriteString(" { ")
+			if err := printExpression(b, selectQuery[1], ""); err != nil {
+				return err
+			}
+			b.WriteString(" }")
+		}
+		return nil
+	case op == "from" && len(q) == 3:
+		if extract, _ := asQuery(q[2]); len(extract) > 0 && extract[0] == "extract" {
+			return printFrom(b, q)
+		}
+	}
+	return unsupported(v)
+}
+
+// printValue prints a string, number, boolean or array of them
+func printValue(b *strings.Builder, v interface{}) error {
+	if _, isString := v.(string); !isString {
+		if list, ok := asQuery(v); ok {
+			b.WriteString("[")
+			for i, value := range list {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				if err := printValue(b, value); err != nil {
+					return err
+				}
+			}
+			b.WriteString("]")
+			return nil
+		}
+	}
+	switch v.(type) {
+	case nil, map[string]interface{}:
+		return unsupported(v)
+	}
+	s, err := encode(v)
+	if err != nil {
+		return err
+	}
+	b.WriteString(s)
+	return nil
+}
@@ -0,0 +1,223 @@
+// Package query builds PuppetDB AST queries, e.g.
+//
+//	q := query.And(
+//		query.Equal("facts_environment", "production"),
+//		query.In("certname", query.Extract(query.Select(query.EntityFacts, query.Equal("name", "osfamily")), "certname")),
+//	)
+//	nodes, err := pdbClient.Nodes(q.String(), nil, nil)
+//
+// Values are JSON encoded, so strings need no quoting or escaping. Use Encode rather than String for a query
+// holding values that may not encode, String returns a diagnostic rather than a query for one.
+package query
+
+import "fmt"
+
+// Entities that can be queried, with select_ and subquery and at the root endpoint
+const (
+	EntityNodes            = "nodes"
+	EntityEnvironments     = "environments"
+	EntityProducers        = "producers"
+	EntityFacts            = "facts"
+	EntityFactContents     = "fact_contents"
+	EntityFactPaths        = "fact_paths"
+	EntityFactsets         = "factsets"
+	EntityInventory        = "inventory"
+	EntityCatalogs         = "catalogs"
+	EntityResources        = "resources"
+	EntityEdges            = "edges"
+	EntityReports          = "reports"
+	EntityEvents           = "events"
+	EntityPackages         = "packages"
+	EntityPackageInventory = "package_inventory"
+)
+
+// Query is a PuppetDB AST query or an expression within one, such as a function in an extract.
+// A nil Query is no query.
+type Query []interface{}
+
+// Encode returns the query as the JSON AST the puppetdb client methods take, or an empty string for a nil
+// Query. Only values that cannot be JSON encoded, such as channels, fail.
+func (q Query) Encode() (string, error) {
+	if q == nil {
+		return "", nil
+	}
+	return encode(q)
+}
+
+// String is Encode for a query whose values are known to encode. For a query that cannot be encoded it
+// returns a diagnostic in the style of fmt, such as %!v(query: json: unsupported type: chan int).
+func (q Query) String() string {
+	s, err := q.Encode()
+	if err != nil {
+		return fmt.Sprintf("%%!v(query: %v)", err)
+	}
+	return s
+}
+
+// GroupBy returns an extract query grouped by the given fields
+func (q Query) GroupBy(fields ...string) Query {
+	groupBy := Query{"group_by"}
+	for _, field := range fields {
+		groupBy = append(groupBy, field)
+	}
+	return append(q[:len(q):len(q)], groupBy)
+}
+
+// Equal matches field equal to value, which may be a path such as []string{"os", "family"} for the path of fact_contents
+func Equal(field string, value interface{}) Query {
+	return Query{"=", field, value}
+}
+
+// GreaterThan matches field greater than value
+func GreaterThan(field string, value interface{}) Query {
+	return Query{">", field, value}
+}
+
+// GreaterThanOrEqual matches field greater than or equal to value
+func GreaterThanOrEqual(field string, value interface{}) Query {
+	return Query{">=", field, value}
+}
+
+// LessThan matches field less than value
+func LessThan(field string, value interface{}) Query {
+	return Query{"<", field, value}
+}
+
+// LessThanOrEqual matches field less than or equal to value
+func LessThanOrEqual(field string, value interface{}) Query {
+	return Query{"<=", field, value}
+}
+
+// Regex matches field against the regular expression pattern
+func Regex(field string, pattern string) Query {
+	return Query{"~", field, pattern}
+}
+
+// RegexPath matches a path field, such as the path of fact_contents, against a regular expression for each element
+func RegexPath(field string, patterns ...string) Query {
+	return Query{"~>", field, patterns}
+}
+
+// Null matches field being null if isNull is true, or not null otherwise
+func Null(field string, isNull bool) Query {
+	return Query{"null?", field, isNull}
+}
+
+// And matches all of the queries
+func And(queries ...Query) Query {
+	return operator("and", queries)
+}
+
+// Or matches any of the queries
+func Or(queries ...Query) Query {
+	return operator("or", queries)
+}
+
+// Not matches what query does not
+func Not(query Query) Query {
+	return Query{"not", query}
+}
+
+func operator(op string, queries []Query) Query {
+	q := Query{op}
+	for _, query := range queries {
+		q = append(q, query)
+	}
+	return q
+}
+
+// In matches field being one of the results of subquery, an Extract
+func In(field string, subquery Query) Query {
+	return Query{"in", field, subquery}
+}
+
+// InFields matches the fields being one of the results of subquery, an Extract of as many fields
+func InFields(fields []string, subquery Query) Query {
+	return Query{"in", fields, subquery}
+}
+
+// InArray matches field being one of values
+func InArray(field string, values ...interface{}) Query {
+	if values == nil {
+		values = []interface{}{}
+	}
+	return Query{"in", field, Query{"array", values}}
+}
+
+// Extract returns the given fields of the results of query, which may be nil to extract from every result.
+// A field is either the name of a field or a function such as Count.
+func Extract(query Query, fields ...interface{}) Query {
+	if fields == nil {
+		fields = []interface{}{}
+	}
+	q := Query{"extract", fields}
+	if query != nil {
+		q = append(q, query)
+	}
+	return q
+}
+
+// Select queries entity, one of the Entity constants, for the subquery of In
+func Select(entity string, query Query) Query {
+	q := Query{"select_" + entity}
+	if query != nil {
+		q = append(q, query)
+	}
+	return q
+}
+
+// Subquery matches the results related to those of query on entity, e.g. nodes with facts matching query
+func Subquery(entity string, query Query) Query {
+	q := Query{"subquery", entity}
+	if query != nil {
+		q = append(q, query)
+	}
+	return q
+}
+
+// From queries entity at the root endpoint, /pdb/query/v4
+func From(entity string, query Query) Query {
+	q := Query{"from", entity}
+	if query != nil {
+		q = append(q, query)
+	}
+	return q
+}
+
+// Count counts the results, or the results with a non-null value for field if one is given
+func Count(field ...string) Query {
+	return function("count", field...)
+}
+
+// Avg averages field
+func Avg(field string) Query {
+	return function("avg", field)
+}
+
+// Sum sums field
+func Sum(field string) Query {
+	return function("sum", field)
+}
+
+// Min is the minimum of field
+func Min(field string) Query {
+	return function("min", field)
+}
+
+// Max is the maximum of field
+func Max(field string) Query {
+	return function("max", field)
+}
+
+// ToString formats field, a timestamp or number, with the given format
+func ToString(field, format string) Query {
+	return function("to_string", field, format)
+}
+
+func function(name string, args ...string) Query {
+	q := Query{"function", name}
+	for _, arg := range args {
+		q = append(q, arg)
+	}
+	return q
+}
@@ -0,0 +1,84 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperators(t *testing.T) {
+	for expected, q := range map[string]Query{
+		`["=","certname","node1.example.com"]`:           Equal("certname", "node1.example.com"),
+		`["=","path",["os","family"]]`:                   Equal("path", []string{"os", "family"}),
+		`[">","value",2]`:                                GreaterThan("value", 2),
+		`[">=","producer_timestamp","2020-01-01"]`:       GreaterThanOrEqual("producer_timestamp", "2020-01-01"),
+		`["<","value",1.5]`:                              LessThan("value", 1.5),
+		`["<=","value",0]`:                               LessThanOrEqual("value", 0),
+		`["~","certname","^web\\d+$"]`:                   Regex("certname", `^web\d+$`),
+		`["~>","path",["networking","interfaces",".*"]]`: RegexPath("path", "networking", "interfaces", ".*"),
+		`["null?","deactivated",true]`:                   Null("deactivated", true),
+		`["not",["=","deactivated",false]]`:              Not(Equal("deactivated", false)),
+		`["=","name","a \"quoted\" <name> & more"]`:      Equal("name", `a "quoted" <name> & more`),
+	} {
+		require.Equal(t, expected, q.String())
+	}
+}
+
+func TestBooleanOperators(t *testing.T) {
+	q := And(
+		Equal("facts_environment", "production"),
+		Or(Regex("certname", "^web"), Regex("certname", "^db")),
+	)
+	require.Equal(t, `["and",["=","facts_environment","production"],["or",["~","certname","^web"],["~","certname","^db"]]]`, q.String())
+	require.Equal(t, `["or"]`, Or().String())
+}
+
+func TestSubqueries(t *testing.T) {
+	q := In("certname", Extract(Select(EntityFacts, And(Equal("name", "osfamily"), Equal("value", "RedHat"))), "certname"))
+	require.Equal(t, `["in","certname",["extract",["certname"],["select_facts",["and",["=","name","osfamily"],["=","value","RedHat"]]]]]`, q.String())
+
+	q = InFields([]string{"certname", "name"}, Extract(Select(EntityResources, nil), "certname", "title"))
+	require.Equal(t, `["in",["certname","name"],["extract",["certname","title"],["select_resources"]]]`, q.String())
+
+	q = InArray("certname", "node1", "node2")
+	require.Equal(t, `["in","certname",["array",["node1","node2"]]]`, q.String())
+	require.Equal(t, `["in","certname",["array",[]]]`, InArray("certname").String())
+
+	q = Subquery(EntityFactContents, Equal("path", []string{"os", "family"}))
+	require.Equal(t, `["subquery","fact_contents",["=","path",["os","family"]]]`, q.String())
+
+	q = From(EntityNodes, Equal("certname", "node1"))
+	require.Equal(t, `["from","nodes",["=","certname","node1"]]`, q.String())
+	require.Equal(t, `["from","nodes"]`, From(EntityNodes, nil).String())
+}
+
+func TestExtract(t *testing.T) {
+	q := Extract(Equal("name", "osfamily"), "value", Count()).GroupBy("value")
+	require.Equal(t, `["extract",["value",["function","count"]],["=","name","osfamily"],["group_by","value"]]`, q.String())
+
+	q = Extract(nil, "status", Count("certname"), Avg("value"), Sum("value"), Min("value"), Max("value"),
+		ToString("producer_timestamp", "YYYY-MM-DD")).GroupBy("status", "environment")
+	require.Equal(t, `["extract",["status",["function","count","certname"],["function","avg","value"],`+
+		`["function","sum","value"],["function","min","value"],["function","max","value"],`+
+		`["function","to_string","producer_timestamp","YYYY-MM-DD"]],["group_by","status","environment"]]`, q.String())
+
+	// Test GroupBy leaves the query it is called on unchanged
+	base := Extract(nil, "certname")
+	base.GroupBy("a")
+	require.Equal(t, `["extract",["certname"]]`, base.String())
+	require.Equal(t, `["extract",[]]`, Extract(nil).String())
+}
+
+func TestString(t *testing.T) {
+	var q Query
+	require.Equal(t, "", q.String())
+	s, err := q.Encode()
+	require.NoError(t, err)
+	require.Equal(t, "", s)
+
+	// Test a value that cannot be encoded is an error and never an empty query
+	q = Equal("certname", make(chan int))
+	_, err = q.Encode()
+	require.Error(t, err)
+	require.Equal(t, "%!v(query: "+err.Error()+")", q.String())
+}
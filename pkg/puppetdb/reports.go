@@ -1,6 +1,9 @@
 package puppetdb
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"time"
 )
 
@@ -13,11 +16,102 @@ const (
 // Metadata about the report
 // Many events, describing what happened during the run
 func (c *Client) Reports(query string, pagination *Pagination, orderBy *OrderBy) ([]Report, error) {
+	return c.ReportsCtx(context.Background(), query, pagination, orderBy)
+}
+
+// ReportsCtx is Reports with a context that cancels the request when done
+func (c *Client) ReportsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Report, error) {
 	payload := []Report{}
-	err := getRequest(c, reports, query, pagination, orderBy, &payload)
+	err := getRequest(ctx, c, reports, query, pagination, orderBy, &payload)
 	return payload, err
 }
 
+// DefaultExpandWorkers is how many reports ExpandReports expands at once if no number is given
+const DefaultExpandWorkers = 4
+
+// ExpandReport fetches the resource events, resources, metrics and logs of report that PuppetDB returned
+// only the Href of, filling in their Data. Those already with Data are left as they are.
+func (c *Client) ExpandReport(report *Report) error {
+	return c.ExpandReportCtx(context.Background(), report)
+}
+
+// ExpandReportCtx is ExpandReport with a context that cancels the requests when done
+func (c *Client) ExpandReportCtx(ctx context.Context, report *Report) error {
+	for _, collection := range []struct {
+		href string
+		data interface{}
+		done bool
+	}{
+		{report.ResourceEvents.Href, &report.ResourceEvents.Data, report.ResourceEvents.Data != nil},
+		{report.Resources.Href, &report.Resources.Data, report.Resources.Data != nil},
+		{report.Metrics.Href, &report.Metrics.Data, report.Metrics.Data != nil},
+		{report.Logs.Href, &report.Logs.Data, report.Logs.Data != nil},
+	} {
+		if collection.done || collection.href == "" {
+			continue
+		}
+		if err := getRequest(ctx, c, collection.href, "", nil, nil, collection.data); err != nil {
+			return fmt.Errorf("report %s: %w", report.Hash, err)
+		}
+	}
+	return nil
+}
+
+// ExpandReports is ExpandReport for each of reports, expanding up to workers reports at once, or
+// DefaultExpandWorkers if workers is not positive. The first error stops the remaining reports being expanded.
+func (c *Client) ExpandReports(reports []Report, workers int) error {
+	return c.ExpandReportsCtx(context.Background(), reports, workers)
+}
+
+// ExpandReportsCtx is ExpandReports with a context that cancels the requests when done
+func (c *Client) ExpandReportsCtx(ctx context.Context, reports []Report, workers int) error {
+	if workers <= 0 {
+		workers = DefaultExpandWorkers
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	indexes := make(chan int)
+	for w := 0; w < workers && w < len(reports); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					continue
+				}
+				if err := c.ExpandReportCtx(ctx, &reports[i]); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+send:
+	for i := range reports {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
 // Report summaries for all event reports that matched the input parameters.
 type Report struct {
 	Hash                 string         `json:"hash"`
@@ -1,9 +1,14 @@
 package puppetdb
 
 import (
+	"errors"
+	"net/http"
+	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -48,3 +53,112 @@ var expectedReport = []Report{{
 		Href: "http://foobar.logs.com",
 	},
 }}
+
+// setupReportHrefResponders responds to the Hrefs of reportWithHrefs, calling each before it responds
+func setupReportHrefResponders(t *testing.T, each func(path string) error) {
+	httpmock.Reset()
+	for _, collection := range []string{"events", "resources", "metrics", "logs"} {
+		path := "/pdb/query/v4/reports/4324324324324324324/" + collection
+		body, err := os.ReadFile("testdata/report-" + collection + ".json")
+		require.Nil(t, err)
+		httpmock.RegisterResponder(http.MethodGet, hostURL+path, func(*http.Request) (*http.Response, error) {
+			if err := each(path); err != nil {
+				return httpmock.NewStringResponse(http.StatusInternalServerError, err.Error()), nil
+			}
+			response := httpmock.NewBytesResponse(http.StatusOK, body)
+			response.Header.Set("Content-Type", "application/json")
+			return response, nil
+		})
+	}
+}
+
+func reportWithHrefs() Report {
+	return Report{
+		Hash:           "4324324324324324324",
+		ResourceEvents: ResourceEvents{Href: "/pdb/query/v4/reports/4324324324324324324/events"},
+		Resources:      Resources{Href: "/pdb/query/v4/reports/4324324324324324324/resources"},
+		Metrics:        Metrics{Href: "/pdb/query/v4/reports/4324324324324324324/metrics"},
+		Logs:           Logs{Href: "/pdb/query/v4/reports/4324324324324324324/logs"},
+	}
+}
+
+func TestExpandReport(t *testing.T) {
+	var requested []string
+	setupReportHrefResponders(t, func(path string) error {
+		requested = append(requested, path)
+		return nil
+	})
+
+	report := reportWithHrefs()
+	require.Nil(t, pdbClient.ExpandReport(&report))
+	require.Len(t, report.ResourceEvents.Data, 1)
+	require.Equal(t, "content", report.ResourceEvents.Data[0].Property)
+	require.Equal(t, []string{"Stage[main]", "Ntp::Config", "File[/etc/ntp.conf]"}, report.ResourceEvents.Data[0].ContainmentPath)
+	require.Len(t, report.Resources.Data, 1)
+	require.Equal(t, "/etc/ntp.conf", report.Resources.Data[0].ResourceTitle)
+	require.Equal(t, "success", report.Resources.Data[0].Events[0].Status)
+	require.Len(t, report.Metrics.Data, 2)
+	require.Equal(t, float32(12.5), report.Metrics.Data[1].Value)
+	require.Len(t, report.Logs.Data, 1)
+	require.Equal(t, "Applied catalog in 12.50 seconds", report.Logs.Data[0].Message)
+	require.Len(t, requested, 4)
+
+	// Test collections already expanded are not fetched again
+	requested = nil
+	report.Logs.Data = nil
+	require.Nil(t, pdbClient.ExpandReport(&report))
+	require.Equal(t, []string{"/pdb/query/v4/reports/4324324324324324324/logs"}, requested)
+
+	// Test an error names the report
+	setupReportHrefResponders(t, func(string) error { return errors.New("oops") })
+	report = reportWithHrefs()
+	err := pdbClient.ExpandReport(&report)
+	require.ErrorIs(t, err, ErrNonTransientResponse)
+	require.Contains(t, err.Error(), "report 4324324324324324324")
+}
+
+func TestExpandReports(t *testing.T) {
+	var mu sync.Mutex
+	var running, maxRunning, count int
+	setupReportHrefResponders(t, func(string) error {
+		mu.Lock()
+		running++
+		count++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+
+	reports := make([]Report, 10)
+	for i := range reports {
+		reports[i] = reportWithHrefs()
+	}
+	require.Nil(t, pdbClient.ExpandReports(reports, 3))
+	for _, report := range reports {
+		require.Len(t, report.Logs.Data, 1)
+	}
+	require.Equal(t, 40, count)
+	require.LessOrEqual(t, maxRunning, 3)
+	require.Nil(t, pdbClient.ExpandReports(nil, 0))
+
+	// Test the first error stops the other reports
+	count = 0
+	setupReportHrefResponders(t, func(string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		return errors.New("oops")
+	})
+	for i := range reports {
+		reports[i] = reportWithHrefs()
+	}
+	err := pdbClient.ExpandReports(reports, 1)
+	require.ErrorIs(t, err, ErrNonTransientResponse)
+	require.Equal(t, 1, count)
+}
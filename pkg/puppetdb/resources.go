@@ -0,0 +1,85 @@
+package puppetdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	resources = "/pdb/query/v4/resources"
+)
+
+// Resources will return the resources of the most recent catalogs matching the given query,
+// e.g. ["and", ["=", "type", "File"], ["=", "title", "/etc/hosts"]] for the nodes managing a file
+func (c *Client) Resources(query string, pagination *Pagination, orderBy *OrderBy) ([]Resource, error) {
+	return c.ResourcesCtx(context.Background(), query, pagination, orderBy)
+}
+
+// ResourcesCtx is Resources with a context that cancels the request when done
+func (c *Client) ResourcesCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) ([]Resource, error) {
+	payload := []Resource{}
+	err := getRequest(ctx, c, resources, query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// ResourcesOfType will return the resources of the given type, such as File, and title matching the given query.
+// An empty title returns resources of the type with any title.
+func (c *Client) ResourcesOfType(resourceType, title string, query string, pagination *Pagination, orderBy *OrderBy) ([]Resource, error) {
+	return c.ResourcesOfTypeCtx(context.Background(), resourceType, title, query, pagination, orderBy)
+}
+
+// ResourcesOfTypeCtx is ResourcesOfType with a context that cancels the request when done
+func (c *Client) ResourcesOfTypeCtx(ctx context.Context, resourceType, title string, query string, pagination *Pagination, orderBy *OrderBy) ([]Resource, error) {
+	payload := []Resource{}
+	err := getRequest(ctx, c, resourcesPath(resourceType, title), query, pagination, orderBy, &payload)
+	return payload, err
+}
+
+// resourcesPath returns /resources/:type/:title, escaping the title as it is often a file path
+func resourcesPath(resourceType, title string) string {
+	path := resources + "/" + url.PathEscape(resourceType)
+	if title != "" {
+		path += "/" + url.PathEscape(title)
+	}
+	return path
+}
+
+// PaginatedResources works just like Resources, but returns a ResourcesCursor that
+// provides methods for iterating over N pages of resources. If pagination is
+// nil, then a default configuration with a limit of 100 is used instead.
+func (c *Client) PaginatedResources(query string, pagination *Pagination, orderBy *OrderBy) (*ResourcesCursor, error) {
+	return c.PaginatedResourcesCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedResourcesCtx is PaginatedResources with a context that cancels the request for the first page of resources,
+// use NextCtx to fetch the other pages with a context
+func (c *Client) PaginatedResourcesCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*ResourcesCursor, error) {
+	cursor, err := newPageCursor[Resource](ctx, c, resources, query, pagination, orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
+	}
+
+	return &ResourcesCursor{Cursor: cursor}, nil
+}
+
+// Resource is a resource of a catalog. Parameters are the attributes of the resource, such as ensure.
+type Resource struct {
+	Certname    string                 `json:"certname,omitempty"`
+	Resource    string                 `json:"resource"`
+	Type        string                 `json:"type"`
+	Title       string                 `json:"title"`
+	Exported    bool                   `json:"exported"`
+	Tags        []string               `json:"tags"`
+	File        string                 `json:"file"`
+	Line        int                    `json:"line"`
+	Environment string                 `json:"environment,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"`
+	Count       int                    `json:"count,omitempty"`
+}
+
+// ResourcesCursor is a pagination cursor that provides convenience methods for
+// stepping through pages of resources.
+type ResourcesCursor struct {
+	*Cursor[Resource]
+}
@@ -0,0 +1,78 @@
+package puppetdb
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResources(t *testing.T) {
+	query := `["and",["=","type","File"],["=","title","/etc/ntp.conf"]]`
+	setupGetResponder(t, resources, "query="+query, "resources-response.json")
+	actual, err := pdbClient.Resources(query, nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedResources, actual)
+}
+
+func TestResourcesOfType(t *testing.T) {
+	setupGetResponder(t, "/pdb/query/v4/resources/File/%2Fetc%2Fntp.conf", "", "resources-response.json")
+	actual, err := pdbClient.ResourcesOfType("File", "/etc/ntp.conf", "", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedResources, actual)
+
+	setupGetResponder(t, "/pdb/query/v4/resources/File", "", "resources-response.json")
+	actual, err = pdbClient.ResourcesOfType("File", "", "", nil, nil)
+	require.Nil(t, err)
+	require.Equal(t, expectedResources, actual)
+}
+
+func TestPaginatedResources(t *testing.T) {
+	pagination := Pagination{Limit: 2, IncludeTotal: true}
+	setupPaginatedGetResponder(t, resources, "", mockPaginatedGetOptions{
+		limit: pagination.Limit,
+		total: 4,
+		pageFilenames: []string{
+			"resources-page-1-response.json",
+			"resources-page-2-response.json",
+		},
+	})
+
+	cursor, err := pdbClient.PaginatedResources("", &pagination, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, cursor.TotalPages())
+
+	actual, err := cursor.Next()
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Equal(t, "1.delivery.puppetlabs.net", actual[0].Certname)
+
+	actual, err = cursor.Next()
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, "3.delivery.puppetlabs.net", actual[0].Certname)
+}
+
+var expectedResources = []Resource{
+	{
+		Certname:    "lenient-veranda.delivery.puppetlabs.net",
+		Resource:    "0a4a1c58b2d6c7d1e9f3a4b5c6d7e8f9a0b1c2d3",
+		Type:        "File",
+		Title:       "/etc/ntp.conf",
+		Tags:        []string{"file", "ntp", "class"},
+		File:        "/etc/puppetlabs/code/environments/production/modules/ntp/manifests/config.pp",
+		Line:        12,
+		Environment: "production",
+		Parameters:  map[string]interface{}{"ensure": "file", "mode": "0644"},
+	},
+	{
+		Certname:    "inland-ancestor.delivery.puppetlabs.net",
+		Resource:    "0a4a1c58b2d6c7d1e9f3a4b5c6d7e8f9a0b1c2d3",
+		Type:        "File",
+		Title:       "/etc/ntp.conf",
+		Tags:        []string{"file", "ntp", "class"},
+		File:        "/etc/puppetlabs/code/environments/production/modules/ntp/manifests/config.pp",
+		Line:        12,
+		Environment: "production",
+		Parameters:  map[string]interface{}{"ensure": "file", "mode": "0644"},
+	},
+}
@@ -1,6 +1,8 @@
 package puppetdb
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,27 +13,44 @@ const (
 )
 
 func (c *Client) PaginatedRootQuery(query string, pagination *Pagination, orderBy *OrderBy) (*RootQueryCursor, error) {
-	pc, err := newPageCursor(c, rootQueryEndpoint, query, pagination, orderBy)
+	return c.PaginatedRootQueryCtx(context.Background(), query, pagination, orderBy)
+}
+
+// PaginatedRootQueryCtx is PaginatedRootQuery with a context that cancels the request for the first page of results,
+// use NextIntoCtx to fetch the other pages with a context
+func (c *Client) PaginatedRootQueryCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy) (*RootQueryCursor, error) {
+	cursor, err := newPageCursor[json.RawMessage](ctx, c, rootQueryEndpoint, query, pagination, orderBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize page cursor: %w", err)
 	}
 
-	cursor := RootQueryCursor{
-		pageCursor: pc,
-	}
-
-	return &cursor, nil
+	return &RootQueryCursor{Cursor: cursor}, nil
 }
 
+// RootQueryCursor steps through the pages of results of a root query, whose type depends on the query
 type RootQueryCursor struct {
-	*pageCursor
+	*Cursor[json.RawMessage]
 }
 
 func (rqc *RootQueryCursor) NextInto(target any) error {
-	err := rqc.next(target)
+	return rqc.NextIntoCtx(context.Background(), target)
+}
+
+// NextIntoCtx is NextInto with a context that cancels the request for the page when done
+func (rqc *RootQueryCursor) NextIntoCtx(ctx context.Context, target any) error {
+	page, err := rqc.NextCtx(ctx)
 	if err != nil && !errors.Is(err, io.EOF) {
 		return err
 	}
+	if page != nil {
+		data, jsonErr := json.Marshal(page)
+		if jsonErr != nil {
+			return jsonErr
+		}
+		if jsonErr = json.Unmarshal(data, target); jsonErr != nil {
+			return jsonErr
+		}
+	}
 
 	return err
 }
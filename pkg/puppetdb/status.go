@@ -1,13 +1,20 @@
 package puppetdb
 
+import "context"
+
 const (
 	puppetDBStatus = "/status/v1/services/puppetdb-status"
 )
 
 // PDbStatus will return the status of the pdb server, specifically the service version.
 func (c *Client) PDbStatus() (*PDbStatus, error) {
+	return c.PDbStatusCtx(context.Background())
+}
+
+// PDbStatusCtx is PDbStatus with a context that cancels the request when done
+func (c *Client) PDbStatusCtx(ctx context.Context) (*PDbStatus, error) {
 	payload := &PDbStatus{}
-	err := getRequest(c, puppetDBStatus, "", nil, nil, &payload)
+	err := getRequest(ctx, c, puppetDBStatus, "", nil, nil, &payload)
 
 	return payload, err
 }
@@ -1,9 +1,11 @@
 package puppetdb
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,10 +24,15 @@ func TestStatus(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorIs(t, err, ErrNonTransientResponse)
 	require.Contains(t, err.Error(), errExpectedURL.Error())
+	var apiErr *apierror.Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, apierror.ServicePuppetDB, apiErr.Service)
+	require.Equal(t, puppetDBStatus, apiErr.Path)
+	require.True(t, apierror.IsNotFound(err))
 }
 
 var (
 	expectedStatuses      = &PDbStatus{"6.8.1-20200122_170412-gc886602"}
 	expectedErrorStatuses = &PDbStatus{""}
-	errExpectedURL        = fmt.Errorf("https://test-host:8081/status/v1/services/puppetdb-status: 404: \"{\"Op\":\"nil\",\"URL\":\"https://test-host:8081\",\"Err\":null}\"")
+	errExpectedURL        = fmt.Errorf("https://test-host:8081/status/v1/services/puppetdb-status: 404: {\"Op\":\"nil\",\"URL\":\"https://test-host:8081\",\"Err\":null}: puppetdb")
 )
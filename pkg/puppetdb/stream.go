@@ -0,0 +1,126 @@
+package puppetdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxErrorBody is how much of the body of an error response to a streamed request is read for the error
+const maxErrorBody = 64 * 1024
+
+// errStopStream stops a stream once the loop over StreamAll is broken out of
+var errStopStream = errors.New("puppetdb: stream stopped")
+
+// Stream runs query on endpoint, as NewCursor takes it, and passes each result to fn as it is decoded from
+// the response. Only one result is held in memory at a time however many there are. An error from fn stops
+// the stream and is returned as it is. As the timeout of the client covers reading the whole response,
+// it has to allow for the size of the result set.
+func Stream[T any](ctx context.Context, c *Client, endpoint, query string, pagination *Pagination, orderBy *OrderBy, fn func(T) error) error {
+	path := endpointPath(endpoint)
+	r, err := newQueryRequest(ctx, c, query, nil, pagination, orderBy).SetDoNotParseResponse(true).Get(path)
+	if err != nil {
+		return requestError(c, path, err)
+	}
+	body := r.RawBody()
+	defer body.Close()
+
+	if r.IsError() {
+		errorBody, _ := io.ReadAll(io.LimitReader(body, maxErrorBody))
+		return responseError(c, path, r, errorBody)
+	}
+	if pagination != nil && pagination.IncludeTotal {
+		pagination.Total = getTotal(r.Header().Get("X-Records"))
+	}
+
+	d := json.NewDecoder(body)
+	token, err := d.Token()
+	if err != nil {
+		return fmt.Errorf("%s%s: %w", c.resty.HostURL, path, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("%s%s: expected an array of results, found %v", c.resty.HostURL, path, token)
+	}
+	for d.More() {
+		var result T
+		if err := d.Decode(&result); err != nil {
+			return fmt.Errorf("%s%s: %w", c.resty.HostURL, path, err)
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	if _, err := d.Token(); err != nil {
+		return fmt.Errorf("%s%s: %w", c.resty.HostURL, path, err)
+	}
+	return nil
+}
+
+// StreamAll is Stream as an iterator, which can be ranged over with Go 1.23 or later. An error ends the
+// iteration, after it is yielded with the zero T. Breaking out of the loop closes the response.
+func StreamAll[T any](ctx context.Context, c *Client, endpoint, query string, pagination *Pagination, orderBy *OrderBy) func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		err := Stream(ctx, c, endpoint, query, pagination, orderBy, func(result T) error {
+			if !yield(result, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopStream) {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// StreamNodes is Nodes streamed, passing each node to fn as it is decoded
+func (c *Client) StreamNodes(query string, pagination *Pagination, orderBy *OrderBy, fn func(Node) error) error {
+	return c.StreamNodesCtx(context.Background(), query, pagination, orderBy, fn)
+}
+
+// StreamNodesCtx is StreamNodes with a context that cancels the request when done
+func (c *Client) StreamNodesCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy, fn func(Node) error) error {
+	return Stream(ctx, c, nodes, query, pagination, orderBy, fn)
+}
+
+// StreamFacts is Facts streamed, passing each fact to fn as it is decoded
+func (c *Client) StreamFacts(query string, pagination *Pagination, orderBy *OrderBy, fn func(Fact) error) error {
+	return c.StreamFactsCtx(context.Background(), query, pagination, orderBy, fn)
+}
+
+// StreamFactsCtx is StreamFacts with a context that cancels the request when done
+func (c *Client) StreamFactsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy, fn func(Fact) error) error {
+	return Stream(ctx, c, facts, query, pagination, orderBy, fn)
+}
+
+// StreamInventory is Inventory streamed, passing each node to fn as it is decoded
+func (c *Client) StreamInventory(query string, pagination *Pagination, orderBy *OrderBy, fn func(Inventory) error) error {
+	return c.StreamInventoryCtx(context.Background(), query, pagination, orderBy, fn)
+}
+
+// StreamInventoryCtx is StreamInventory with a context that cancels the request when done
+func (c *Client) StreamInventoryCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy, fn func(Inventory) error) error {
+	return Stream(ctx, c, inventory, query, pagination, orderBy, fn)
+}
+
+// StreamInventoryMap is InventoryMap streamed, passing each node to fn as it is decoded
+func (c *Client) StreamInventoryMap(query string, pagination *Pagination, orderBy *OrderBy, fn func(map[string]interface{}) error) error {
+	return c.StreamInventoryMapCtx(context.Background(), query, pagination, orderBy, fn)
+}
+
+// StreamInventoryMapCtx is StreamInventoryMap with a context that cancels the request when done
+func (c *Client) StreamInventoryMapCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy, fn func(map[string]interface{}) error) error {
+	return Stream(ctx, c, inventory, query, pagination, orderBy, fn)
+}
+
+// StreamFactsets is Factsets streamed, passing each factset to fn as it is decoded
+func (c *Client) StreamFactsets(query string, pagination *Pagination, orderBy *OrderBy, fn func(Factset) error) error {
+	return c.StreamFactsetsCtx(context.Background(), query, pagination, orderBy, fn)
+}
+
+// StreamFactsetsCtx is StreamFactsets with a context that cancels the request when done
+func (c *Client) StreamFactsetsCtx(ctx context.Context, query string, pagination *Pagination, orderBy *OrderBy, fn func(Factset) error) error {
+	return Stream(ctx, c, factsets, query, pagination, orderBy, fn)
+}
@@ -0,0 +1,234 @@
+package puppetdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+// trackedBody is a response body that counts how many times it is closed
+type trackedBody struct {
+	io.Reader
+	closed *int
+}
+
+func (b trackedBody) Close() error {
+	*b.closed++
+	return nil
+}
+
+// setupTrackedResponder responds to requests for url with the given status codes in turn, and the
+// nodes after that. It returns funcs counting the bodies opened and closed.
+func setupTrackedResponder(url string, statusCodes ...int) (func() int, func() int) {
+	httpmock.Reset()
+	opened, closed := 0, 0
+	httpmock.RegisterResponder(http.MethodGet, hostURL+url, func(*http.Request) (*http.Response, error) {
+		opened++
+		statusCode, body := http.StatusOK, `[{"certname": "a"}]`
+		if opened <= len(statusCodes) {
+			statusCode, body = statusCodes[opened-1], fmt.Sprintf(`{"msg": "attempt %d"}`, opened)
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       trackedBody{Reader: strings.NewReader(body), closed: &closed},
+		}, nil
+	})
+	return func() int { return opened }, func() int { return closed }
+}
+
+func TestStreamFacts(t *testing.T) {
+	query := `["=", "certname", "foobar.puppetlabs.net"]`
+	setupGetResponder(t, facts, "query="+query, "facts-response.json")
+	var actual []Fact
+	err := pdbClient.StreamFacts(query, nil, nil, func(fact Fact) error {
+		actual = append(actual, fact)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, expectedFacts, actual)
+}
+
+func TestStreamNodes(t *testing.T) {
+	setupGetResponder(t, nodes, "", "nodes-response.json")
+	var actual []Node
+	err := pdbClient.StreamNodes("", nil, nil, func(node Node) error {
+		actual = append(actual, node)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, expectedNodes, actual)
+
+	// Test an error from fn stops the stream
+	errStop := errors.New("stop")
+	count := 0
+	err = pdbClient.StreamNodes("", nil, nil, func(node Node) error {
+		count++
+		return errStop
+	})
+	require.Equal(t, errStop, err)
+	require.Equal(t, 1, count)
+}
+
+func TestStreamInventoryMap(t *testing.T) {
+	setupGetResponder(t, inventory, "", "inventory.json")
+	expected, err := pdbClient.InventoryMap("", nil, nil)
+	require.Nil(t, err)
+
+	var actual []map[string]interface{}
+	err = pdbClient.StreamInventoryMap("", nil, nil, func(node map[string]interface{}) error {
+		actual = append(actual, node)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestStreamErrors(t *testing.T) {
+	// Test an error response
+	setupResponderWithStatusCodeAndBody(t, nodes, http.StatusBadRequest, map[string]string{"msg": "bad query"})
+	err := pdbClient.StreamNodes(`["bad"]`, nil, nil, func(Node) error { return nil })
+	require.ErrorIs(t, err, ErrNonTransientResponse)
+	require.Equal(t, http.StatusBadRequest, apierror.StatusCode(err))
+	require.Contains(t, err.Error(), "bad query")
+
+	setupURLResponderWithStatusCode(t, nodes, http.StatusServiceUnavailable)
+	err = pdbClient.StreamNodes("", nil, nil, func(Node) error { return nil })
+	require.ErrorIs(t, err, ErrTransientResponse)
+
+	// Test a body that is not an array of results
+	setupResponderWithStatusCodeAndBody(t, nodes, http.StatusOK, map[string]string{"certname": "a"})
+	err = pdbClient.StreamNodes("", nil, nil, func(Node) error { return nil })
+	require.EqualError(t, err, hostURL+nodes+": expected an array of results, found {")
+
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodGet, hostURL+nodes, httpmock.NewStringResponder(http.StatusOK, `[{"certname": "a"}, {"certname": `))
+	count := 0
+	err = pdbClient.StreamNodes("", nil, nil, func(Node) error {
+		count++
+		return nil
+	})
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Equal(t, 1, count)
+
+	// Test deadline reaches the request
+	setupBlockingResponder(nodes)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = pdbClient.StreamNodesCtx(ctx, "", nil, nil, func(Node) error { return nil })
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestStreamIncremental tests results reach fn while the rest of the response is still to be written
+func TestStreamIncremental(t *testing.T) {
+	const total = 10000
+	reader, writer := io.Pipe()
+	firstDecoded := make(chan struct{})
+	go func() {
+		fmt.Fprint(writer, `[{"certname": "1.delivery.puppetlabs.net"}`)
+		select {
+		case <-firstDecoded:
+		case <-time.After(5 * time.Second):
+			writer.CloseWithError(errors.New("the first node was not decoded before the response was complete"))
+			return
+		}
+		for i := 2; i <= total; i++ {
+			fmt.Fprintf(writer, `,{"certname": "%d.delivery.puppetlabs.net"}`, i)
+		}
+		fmt.Fprint(writer, "]")
+		writer.Close()
+	}()
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodGet, hostURL+nodes, func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: reader}, nil
+	})
+
+	count := 0
+	err := pdbClient.StreamNodes("", nil, nil, func(node Node) error {
+		count++
+		if count == 1 {
+			close(firstDecoded)
+		}
+		require.Equal(t, fmt.Sprintf("%d.delivery.puppetlabs.net", count), node.Certname)
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, total, count)
+}
+
+func TestStreamAll(t *testing.T) {
+	setupGetResponder(t, nodes, "", "nodes-response.json")
+	var actual []Node
+	StreamAll[Node](context.Background(), pdbClient, "nodes", "", nil, nil)(func(node Node, err error) bool {
+		require.NoError(t, err)
+		actual = append(actual, node)
+		return true
+	})
+	require.Equal(t, expectedNodes, actual)
+
+	// Test breaking out of the loop
+	actual = nil
+	StreamAll[Node](context.Background(), pdbClient, "nodes", "", nil, nil)(func(node Node, err error) bool {
+		actual = append(actual, node)
+		return false
+	})
+	require.Len(t, actual, 1)
+
+	// Test an error is yielded
+	setupURLResponderWithStatusCode(t, nodes, http.StatusNotFound)
+	var errs []error
+	StreamAll[Node](context.Background(), pdbClient, "nodes", "", nil, nil)(func(node Node, err error) bool {
+		errs = append(errs, err)
+		return true
+	})
+	require.Len(t, errs, 1)
+	require.True(t, apierror.IsNotFound(errs[0]))
+}
+
+func TestStreamTotal(t *testing.T) {
+	setupCursorResponder(nodes, 7, -1)
+	pagination := Pagination{Limit: 5, IncludeTotal: true}
+	count := 0
+	err := pdbClient.StreamNodes("", &pagination, nil, func(Node) error {
+		count++
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 5, count)
+	require.Equal(t, 7, pagination.Total)
+}
+
+func TestStreamRetry(t *testing.T) {
+	pdbClient.SetRetryPolicy(retry.Policy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: time.Millisecond})
+	defer pdbClient.SetRetryPolicy(retry.Policy{})
+
+	// Test the body of every attempt is closed
+	opened, closed := setupTrackedResponder(nodes, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	count := 0
+	err := pdbClient.StreamNodes("", nil, nil, func(Node) error {
+		count++
+		return nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 1, count)
+	require.Equal(t, 3, opened())
+	require.Equal(t, 3, closed())
+
+	// Test the error is read from the body of the last attempt
+	opened, closed = setupTrackedResponder(nodes, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	err = pdbClient.StreamNodes("", nil, nil, func(Node) error { return nil })
+	require.ErrorIs(t, err, ErrTransientResponse)
+	require.Contains(t, err.Error(), "attempt 3")
+	require.Equal(t, 3, opened())
+	require.Equal(t, 3, closed())
+}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"net/http"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
 )
 
 // Client for the RBAC API
@@ -35,12 +38,25 @@ func NewClient(hostURL string, tlsConfig *tls.Config) *Client {
 	return &client
 }
 
+// SetTransport lets the caller overwrite the default transport used by the client.
+// This is useful when injecting mock transports for testing purposes.
+func (c *Client) SetTransport(tripper http.RoundTripper) {
+	c.resty.SetTransport(tripper)
+}
+
+// SetRetryPolicy sets how the client retries requests that fail with a transient error.
+// The client does not retry unless a policy is set, e.g. retry.DefaultPolicy().
+func (c *Client) SetRetryPolicy(policy retry.Policy) {
+	policy.Apply(c.resty)
+}
+
 // APIError represents an error response from the RBAC API
 type APIError struct {
 	Kind       string `json:"kind"`
 	Msg        string `json:"msg"`
 	Details    string `json:"details"`
 	StatusCode int
+	Path       string `json:"-"`
 }
 
 func (oe *APIError) Error() string {
@@ -51,3 +67,25 @@ func (oe *APIError) Error() string {
 func (oe *APIError) GetStatusCode() int {
 	return oe.StatusCode
 }
+
+// As converts the error to an *apierror.Error
+func (oe *APIError) As(target interface{}) bool {
+	if e, ok := target.(**apierror.Error); ok {
+		*e = oe.apiError()
+		return true
+	}
+	return false
+}
+
+// Is matches an *apierror.Error target such as apierror.ErrForbidden
+func (oe *APIError) Is(target error) bool {
+	return oe.apiError().Is(target)
+}
+
+func (oe *APIError) apiError() *apierror.Error {
+	e := &apierror.Error{Service: apierror.ServiceRBAC, StatusCode: oe.StatusCode, Kind: oe.Kind, Msg: oe.Msg, Path: oe.Path}
+	if oe.Details != "" {
+		e.Details = oe.Details
+	}
+	return e
+}
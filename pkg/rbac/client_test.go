@@ -0,0 +1,37 @@
+package rbac
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRetryPolicy(t *testing.T) {
+	rbacClient.SetRetryPolicy(retry.Policy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: time.Millisecond})
+	defer rbacClient.SetRetryPolicy(retry.Policy{})
+
+	// Test GET is retried
+	calls := setupUnavailableResponder(t, rolesPath, "GetRoles-response.json")
+	roles, err := rbacClient.GetRoles("token")
+	require.Nil(t, err)
+	require.NotEmpty(t, roles)
+	require.Equal(t, 2, calls())
+
+	// Test read only POST is retried
+	calls = setupUnavailableResponder(t, tokenAuthenticateURI, "AuthenticateRBACToken-response.json")
+	response, err := rbacClient.AuthenticateRBACToken("blah")
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	require.Equal(t, 2, calls())
+
+	// Test a request for a new token is not retried
+	calls = setupUnavailableResponder(t, requestAuthTokenURI, "GetRBACToken-response.json")
+	token, err := rbacClient.GetRBACToken(&RequestKeys{Login: "jimbo", Password: "package"})
+	require.Nil(t, token)
+	require.Equal(t, http.StatusServiceUnavailable, apierror.StatusCode(err))
+	require.Equal(t, 1, calls())
+}
@@ -49,6 +49,18 @@ func setUpBadRequestResponder(t *testing.T, httpMethod string, path string) {
 	httpmock.RegisterResponder(httpMethod, rbacAPIOrigin+path, responder)
 }
 
+// setupBlockingResponder responds to every request for url only once the request's context is done
+func setupBlockingResponder(url string) {
+	httpmock.Reset()
+	responder := func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+		httpmock.RegisterResponder(method, rbacAPIOrigin+url, responder)
+	}
+}
+
 func setupPostResponder(t *testing.T, url, requestFilename, responseFilename string) {
 	httpmock.Reset()
 	httpmock.RegisterResponder(http.MethodPost, rbacAPIOrigin+url,
@@ -74,6 +86,28 @@ func setupPostResponder(t *testing.T, url, requestFilename, responseFilename str
 	)
 }
 
+// setupUnavailableResponder responds to GET and POST requests for path with a 503 the first time
+// and the given response file after that. It returns a func counting the calls made.
+func setupUnavailableResponder(t *testing.T, path, responseFilename string) func() int {
+	httpmock.Reset()
+	responseBody, err := os.ReadFile("testdata/apidocs/" + responseFilename)
+	require.Nil(t, err)
+
+	calls := 0
+	responder := func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, `{}`), nil
+		}
+		response := httpmock.NewBytesResponse(http.StatusOK, responseBody)
+		response.Header.Set("Content-Type", "application/json")
+		return response, nil
+	}
+	httpmock.RegisterResponder(http.MethodGet, rbacAPIOrigin+path, responder)
+	httpmock.RegisterResponder(http.MethodPost, rbacAPIOrigin+path, responder)
+	return func() int { return calls }
+}
+
 func setupCreateRoleSuccessResponder(t *testing.T, url string, requestFilename string) {
 	httpmock.Reset()
 	httpmock.RegisterResponder(http.MethodPost, rbacAPIOrigin+url,
@@ -116,6 +150,13 @@ var expectedError = &APIError{
 	StatusCode: 400,
 }
 
+// expectedErrorAt returns expectedError as it is returned for a request for path
+func expectedErrorAt(path string) *APIError {
+	e := *expectedError
+	e.Path = path
+	return &e
+}
+
 var createRoleDuplicateError = &APIError{
 	Msg:        "There was a database conflict due to the value(s): Testing",
 	StatusCode: 409,
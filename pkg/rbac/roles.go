@@ -1,6 +1,9 @@
 package rbac
 
-import "strconv"
+import (
+	"context"
+	"strconv"
+)
 
 const (
 	rolePath  = "/rbac-api/v1/roles/{id}"
@@ -9,14 +12,20 @@ const (
 
 // GetRoles fetches information about all user roles.
 func (c *Client) GetRoles(token string) ([]Role, error) {
+	return c.GetRolesCtx(context.Background(), token)
+}
+
+// GetRolesCtx is GetRoles with a context that cancels the request when done
+func (c *Client) GetRolesCtx(ctx context.Context, token string) ([]Role, error) {
 	var roles []Role
 
 	response, err := c.resty.R().
+		SetContext(ctx).
 		SetHeader("X-Authentication", token).
 		SetResult(&roles).
 		Get(rolesPath)
 	if err != nil {
-		return nil, FormatError(response, err.Error())
+		return nil, requestError(response, err)
 	}
 
 	return roles, nil
@@ -24,15 +33,21 @@ func (c *Client) GetRoles(token string) ([]Role, error) {
 
 // GetRole fetches information about a single role, identified by its ID.
 func (c *Client) GetRole(id uint, token string) (*Role, error) {
+	return c.GetRoleCtx(context.Background(), id, token)
+}
+
+// GetRoleCtx is GetRole with a context that cancels the request when done
+func (c *Client) GetRoleCtx(ctx context.Context, id uint, token string) (*Role, error) {
 	var role Role
 
 	response, err := c.resty.R().
+		SetContext(ctx).
 		SetHeader("X-Authentication", token).
 		SetPathParams(map[string]string{"id": strconv.FormatUint(uint64(id), 10)}).
 		SetResult(&role).
 		Get(rolePath)
 	if err != nil {
-		return nil, FormatError(response, err.Error())
+		return nil, requestError(response, err)
 	}
 
 	return &role, nil
@@ -44,7 +59,13 @@ func (c *Client) GetRole(id uint, token string) (*Role, error) {
 // If the role was created successfully then the path of the new role is
 // returned, otherwise an error is returned.
 func (c *Client) CreateRole(role *Role, token string) (string, error) {
+	return c.CreateRoleCtx(context.Background(), role, token)
+}
+
+// CreateRoleCtx is CreateRole with a context that cancels the request when done
+func (c *Client) CreateRoleCtx(ctx context.Context, role *Role, token string) (string, error) {
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetBody(role).
 		SetHeader("X-Authentication", token).
 		Post(rolesPath)
@@ -55,7 +76,7 @@ func (c *Client) CreateRole(role *Role, token string) (string, error) {
 		if !r.IsError() && r.RawResponse.Header.Get("Location") != "" {
 			// Ignore the error.
 		} else {
-			return "", FormatError(r, err.Error())
+			return "", requestError(r, err)
 		}
 	}
 
@@ -1,6 +1,11 @@
 package rbac
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+
+	"github.com/puppetlabs/go-pe-client/pkg/retry"
+)
 
 const (
 	requestAuthTokenURI  = "/rbac-api/v1/auth/token"              // #nosec - this is the uri to g et RBAC tokens
@@ -11,13 +16,19 @@ const (
 
 // GetRBACToken returns an auth token given user/password information
 func (c *Client) GetRBACToken(authRequest *RequestKeys) (*Token, error) {
+	return c.GetRBACTokenCtx(context.Background(), authRequest)
+}
+
+// GetRBACTokenCtx is GetRBACToken with a context that cancels the request when done
+func (c *Client) GetRBACTokenCtx(ctx context.Context, authRequest *RequestKeys) (*Token, error) {
 	payload := Token{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(authRequest).
 		Post(requestAuthTokenURI)
 	if err != nil {
-		return nil, FormatError(r, err.Error())
+		return nil, requestError(r, err)
 	}
 	if r.IsError() {
 		if r.Error() != nil {
@@ -30,15 +41,22 @@ func (c *Client) GetRBACToken(authRequest *RequestKeys) (*Token, error) {
 
 // AuthenticateRBACToken returns a response with the token details or errors otherwise.
 func (c *Client) AuthenticateRBACToken(token string) (*AuthenticateResponse, error) {
+	return c.AuthenticateRBACTokenCtx(context.Background(), token)
+}
+
+// AuthenticateRBACTokenCtx is AuthenticateRBACToken with a context that cancels the request when done
+func (c *Client) AuthenticateRBACTokenCtx(ctx context.Context, token string) (*AuthenticateResponse, error) {
 	authenticateRequest := &AuthenticateRequest{Token: token}
 
 	payload := AuthenticateResponse{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		SetBody(authenticateRequest).
+		AddRetryCondition(retry.Transient).
 		Post(tokenAuthenticateURI)
 	if err != nil {
-		return nil, FormatError(r, err.Error())
+		return nil, requestError(r, err)
 	}
 	if r.IsError() {
 		if r.Error() != nil {
@@ -50,13 +68,19 @@ func (c *Client) AuthenticateRBACToken(token string) (*AuthenticateResponse, err
 }
 
 func (c *Client) RevokeRBACToken(token string) error {
+	return c.RevokeRBACTokenCtx(context.Background(), token)
+}
+
+// RevokeRBACTokenCtx is RevokeRBACToken with a context that cancels the request when done
+func (c *Client) RevokeRBACTokenCtx(ctx context.Context, token string) error {
 	payload := AuthenticateResponse{}
 
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetResult(&payload).
 		Delete(fmt.Sprintf("%s%s", tokenRevokeURI, token))
 	if err != nil {
-		return FormatError(r, err.Error())
+		return requestError(r, err)
 	}
 	if r.IsError() {
 		if r.Error() != nil {
@@ -69,15 +93,21 @@ func (c *Client) RevokeRBACToken(token string) error {
 
 // GenerateRBACToken returns an RBAC token or errors otherwise
 func (c *Client) GenerateRBACToken(token string, request TokenRequest) (string, error) {
+	return c.GenerateRBACTokenCtx(context.Background(), token, request)
+}
+
+// GenerateRBACTokenCtx is GenerateRBACToken with a context that cancels the request when done
+func (c *Client) GenerateRBACTokenCtx(ctx context.Context, token string, request TokenRequest) (string, error) {
 	var payload Token
 
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetHeader("X-Authentication", token).
 		SetResult(&payload).
 		SetBody(request).
 		Post(tokenGenerateURI)
 	if err != nil {
-		return "", FormatError(r, err.Error())
+		return "", requestError(r, err)
 	}
 	if r.IsError() {
 		if r.Error() != nil {
@@ -1,10 +1,14 @@
 package rbac
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,7 +31,27 @@ func TestGetRBACToken(t *testing.T) {
 	setUpBadRequestResponder(t, http.MethodPost, requestAuthTokenURI)
 	actual, err = rbacClient.GetRBACToken(request)
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(requestAuthTokenURI), err)
+	var apiErr *apierror.Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, apierror.ServiceRBAC, apiErr.Service)
+	require.Equal(t, expectedError.Kind, apiErr.Kind)
+	require.Equal(t, requestAuthTokenURI, apiErr.Path)
+	require.ErrorIs(t, err, apierror.ErrBadRequest)
+	require.False(t, apierror.IsTransient(err))
+}
+
+func TestGetRBACTokenCtx(t *testing.T) {
+	setupBlockingResponder(requestAuthTokenURI)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	actual, err := rbacClient.GetRBACTokenCtx(ctx, &RequestKeys{Login: "jimbo", Password: "package"})
+	require.Nil(t, actual)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	setupBlockingResponder(tokenRevokeURI + "abc")
+	err = rbacClient.RevokeRBACTokenCtx(ctx, "abc")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
 }
 
 func TestAuthenticateRBACToken(t *testing.T) {
@@ -48,7 +72,7 @@ func TestAuthenticateRBACToken(t *testing.T) {
 	setUpBadRequestResponder(t, http.MethodPost, tokenAuthenticateURI)
 	actual, err = rbacClient.AuthenticateRBACToken("blah")
 	require.Nil(t, actual)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(tokenAuthenticateURI), err)
 }
 
 func TestRevokeRBACToken(t *testing.T) {
@@ -63,7 +87,7 @@ func TestRevokeRBACToken(t *testing.T) {
 	// Test error
 	setUpBadRequestResponder(t, http.MethodDelete, fmt.Sprintf("%s%s", tokenRevokeURI, tokenValue))
 	err = rbacClient.RevokeRBACToken(tokenValue)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(fmt.Sprintf("%s%s", tokenRevokeURI, tokenValue)), err)
 }
 
 func TestGenerateRBACToken(t *testing.T) {
@@ -85,5 +109,5 @@ func TestGenerateRBACToken(t *testing.T) {
 	// Test error
 	setUpBadRequestResponder(t, http.MethodPost, tokenGenerateURI)
 	_, err = rbacClient.GenerateRBACToken(tokenValue, tokenRequest)
-	require.Equal(t, expectedError, err)
+	require.Equal(t, expectedErrorAt(tokenGenerateURI), err)
 }
@@ -1,6 +1,7 @@
 package rbac
 
 import (
+	"context"
 	"fmt"
 	"time"
 )
@@ -30,13 +31,19 @@ type User struct {
 
 // GetUsers returns all the users in the system.
 func (c *Client) GetUsers(token string) ([]User, error) {
+	return c.GetUsersCtx(context.Background(), token)
+}
+
+// GetUsersCtx is GetUsers with a context that cancels the request when done
+func (c *Client) GetUsersCtx(ctx context.Context, token string) ([]User, error) {
 	users := []User{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetHeader("X-Authentication", token).
 		SetResult(&users).
 		Get(requestUsersURI)
 	if err != nil {
-		return nil, FormatError(r, err.Error())
+		return nil, requestError(r, err)
 	}
 	if r.IsError() {
 		if r.Error() != nil {
@@ -49,13 +56,19 @@ func (c *Client) GetUsers(token string) ([]User, error) {
 
 // GetCurrentUser will return the current user details.
 func (c *Client) GetCurrentUser(token string) (*User, error) {
+	return c.GetCurrentUserCtx(context.Background(), token)
+}
+
+// GetCurrentUserCtx is GetCurrentUser with a context that cancels the request when done
+func (c *Client) GetCurrentUserCtx(ctx context.Context, token string) (*User, error) {
 	user := User{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetHeader("X-Authentication", token).
 		SetResult(&user).
 		Get(requestCurrentUserURI)
 	if err != nil {
-		return nil, FormatError(r, err.Error())
+		return nil, requestError(r, err)
 	}
 	if r.IsError() {
 		if r.Error() != nil {
@@ -68,13 +81,19 @@ func (c *Client) GetCurrentUser(token string) (*User, error) {
 
 // GetSpecificUser will return a specific user details
 func (c *Client) GetSpecificUser(token string, sid string) (*User, error) {
+	return c.GetSpecificUserCtx(context.Background(), token, sid)
+}
+
+// GetSpecificUserCtx is GetSpecificUser with a context that cancels the request when done
+func (c *Client) GetSpecificUserCtx(ctx context.Context, token string, sid string) (*User, error) {
 	user := User{}
 	r, err := c.resty.R().
+		SetContext(ctx).
 		SetHeader("X-Authentication", token).
 		SetResult(&user).
 		Get(fmt.Sprintf("%s%s", requestUserURI, sid))
 	if err != nil {
-		return nil, FormatError(r, err.Error())
+		return nil, requestError(r, err)
 	}
 	if r.IsError() {
 		if r.Error() != nil {
@@ -6,12 +6,14 @@ import (
 	"strings"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
 )
 
-func newAPIError(statusCode int, msg string) *APIError {
+func newAPIError(statusCode int, msg, path string) *APIError {
 	return &APIError{
 		StatusCode: statusCode,
 		Msg:        msg,
+		Path:       path,
 	}
 }
 
@@ -19,12 +21,14 @@ func newAPIError(statusCode int, msg string) *APIError {
 // APIError with as much info as possible
 func FormatError(r *resty.Response, customError ...string) error {
 	msg := strings.Join(customError, ", ")
+	path := apierror.RequestPath(r)
 
 	if apiErr, ok := r.Error().(*APIError); ok {
 		if reflect.DeepEqual(&APIError{}, apiErr) {
-			return newAPIError(r.StatusCode(), msg)
+			return newAPIError(r.StatusCode(), msg, path)
 		}
 		apiErr.StatusCode = r.StatusCode()
+		apiErr.Path = path
 		if len(msg) > 0 {
 			apiErr.Msg = msg
 		}
@@ -32,8 +36,17 @@ func FormatError(r *resty.Response, customError ...string) error {
 		return apiErr
 
 	} else if r.IsError() {
-		return newAPIError(r.StatusCode(), msg)
+		return newAPIError(r.StatusCode(), msg, path)
 	}
 
 	return fmt.Errorf("%s", msg)
 }
+
+// requestError formats an error returned by resty along with its response. Errors that did not come with
+// an HTTP error response, such as a cancelled context, are returned as is so that errors.Is can match them.
+func requestError(r *resty.Response, err error) error {
+	if _, ok := r.Error().(*APIError); !ok && !r.IsError() {
+		return err
+	}
+	return FormatError(r, err.Error())
+}
@@ -0,0 +1,119 @@
+// Package retry provides the retry and backoff policy shared by the PE API clients.
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Policy configures how a client retries requests that failed with a transient error, i.e. a connection
+// error or a 408, 429, 502, 503 or 504 response. The wait between attempts grows exponentially from
+// WaitTime up to MaxWaitTime with random jitter. A Retry-After header on a 429 or 503 response is used
+// as the wait instead, capped at MaxWaitTime.
+// MaxRetries	The number of retries after the first attempt, 0 disables retries.
+// WaitTime	The base wait before the first retry.
+// MaxWaitTime	The upper bound of any single wait.
+// RetryNonIdempotent	Also retry POST requests that may change state, such as orchestrator commands.
+// Without it a command that failed with a transient error is not run again, as it may have been accepted.
+type Policy struct {
+	MaxRetries         int
+	WaitTime           time.Duration
+	MaxWaitTime        time.Duration
+	RetryNonIdempotent bool
+}
+
+// DefaultPolicy returns a policy of 3 retries waiting from 500ms up to 30s
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxRetries:  3,
+		WaitTime:    500 * time.Millisecond,
+		MaxWaitTime: 30 * time.Second,
+	}
+}
+
+// Apply configures the resty client to retry with this policy, replacing any retry settings it already has
+func (p Policy) Apply(r *resty.Client) {
+	if p.MaxRetries <= 0 {
+		r.SetRetryCount(0)
+		r.RetryConditions = nil
+		r.SetRetryAfter(nil)
+		return
+	}
+	r.SetRetryCount(p.MaxRetries)
+	r.SetRetryWaitTime(p.WaitTime)
+	r.SetRetryMaxWaitTime(p.MaxWaitTime)
+	r.RetryConditions = []resty.RetryConditionFunc{p.shouldRetry}
+	r.SetRetryAfter(retryAfter)
+}
+
+// shouldRetry retries transient failures of idempotent requests, or of every request if RetryNonIdempotent is set
+func (p Policy) shouldRetry(resp *resty.Response, err error) bool {
+	if !p.RetryNonIdempotent && resp != nil && resp.Request != nil && !isIdempotent(resp.Request.Method) {
+		return false
+	}
+	return Transient(resp, err)
+}
+
+// Transient is a resty retry condition that is true for connection errors and transient error responses
+// whatever the request method. The clients add it to POST requests that only read data, such as queries,
+// so that they are retried even though POST requests are not retried by default.
+func Transient(resp *resty.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		// An error with a response is a failure to decode the body, which will not change on retry
+		return resp == nil || resp.RawResponse == nil
+	}
+	return resp != nil && IsTransientStatus(resp.StatusCode())
+}
+
+// IsTransientStatus returns true if a response with the given status code is likely to succeed on retry
+func IsTransientStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the wait requested by the Retry-After header of a 429 or 503 response, 0 uses the backoff
+func retryAfter(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return ParseRetryAfter(resp.Header().Get("Retry-After"), time.Now()), nil
+	}
+	return 0, nil
+}
+
+// ParseRetryAfter parses a Retry-After header value, given either in seconds or as an HTTP date, into
+// the duration to wait from now. It returns 0 if the value is missing, invalid or in the past.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
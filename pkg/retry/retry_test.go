@@ -0,0 +1,170 @@
+package retry
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+const hostURL = "https://test-host"
+
+var testPolicy = Policy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: 5 * time.Millisecond}
+
+// newTestClient returns a resty client with the policy applied and a responder for path that answers
+// with the given status codes in turn, repeating the last one. The returned func counts the calls made.
+func newTestClient(t *testing.T, policy Policy, path string, statusCodes ...int) (*resty.Client, func() int) {
+	r := resty.New().SetBaseURL(hostURL)
+	policy.Apply(r)
+	httpmock.ActivateNonDefault(r.GetClient())
+	t.Cleanup(httpmock.DeactivateAndReset)
+
+	calls := 0
+	responder := func(req *http.Request) (*http.Response, error) {
+		statusCode := statusCodes[len(statusCodes)-1]
+		if calls < len(statusCodes) {
+			statusCode = statusCodes[calls]
+		}
+		calls++
+		return httpmock.NewStringResponse(statusCode, `{}`), nil
+	}
+	httpmock.RegisterResponder(http.MethodGet, hostURL+path, responder)
+	httpmock.RegisterResponder(http.MethodPost, hostURL+path, responder)
+	return r, func() int { return calls }
+}
+
+func TestPolicyRetriesTransientErrors(t *testing.T) {
+	r, calls := newTestClient(t, testPolicy, "/jobs", http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	resp, err := r.R().Get("/jobs")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Equal(t, 3, calls())
+
+	// Test retries used up
+	r, calls = newTestClient(t, testPolicy, "/jobs", http.StatusGatewayTimeout)
+	resp, err = r.R().Get("/jobs")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusGatewayTimeout, resp.StatusCode())
+	require.Equal(t, 3, calls())
+
+	// Test non transient error
+	r, calls = newTestClient(t, testPolicy, "/jobs", http.StatusNotFound, http.StatusOK)
+	resp, err = r.R().Get("/jobs")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode())
+	require.Equal(t, 1, calls())
+}
+
+func TestPolicyRetriesConnectionErrors(t *testing.T) {
+	r := resty.New().SetBaseURL(hostURL)
+	testPolicy.Apply(r)
+	httpmock.ActivateNonDefault(r.GetClient())
+	t.Cleanup(httpmock.DeactivateAndReset)
+
+	calls := 0
+	httpmock.RegisterResponder(http.MethodGet, hostURL+"/jobs", func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return nil, http.ErrHandlerTimeout
+		}
+		return httpmock.NewStringResponse(http.StatusOK, `{}`), nil
+	})
+	resp, err := r.R().Get("/jobs")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Equal(t, 2, calls)
+}
+
+func TestPolicyNonIdempotent(t *testing.T) {
+	// Test POST is not retried by default
+	r, calls := newTestClient(t, testPolicy, "/command/task", http.StatusServiceUnavailable, http.StatusOK)
+	resp, err := r.R().Post("/command/task")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+	require.Equal(t, 1, calls())
+
+	// Test POST is retried when opted in
+	policy := testPolicy
+	policy.RetryNonIdempotent = true
+	r, calls = newTestClient(t, policy, "/command/task", http.StatusServiceUnavailable, http.StatusOK)
+	resp, err = r.R().Post("/command/task")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Equal(t, 2, calls())
+
+	// Test read only POST is retried with the Transient condition
+	r, calls = newTestClient(t, testPolicy, "/inventory", http.StatusTooManyRequests, http.StatusOK)
+	resp, err = r.R().AddRetryCondition(Transient).Post("/inventory")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Equal(t, 2, calls())
+}
+
+func TestPolicyDisabled(t *testing.T) {
+	r, calls := newTestClient(t, Policy{}, "/jobs", http.StatusServiceUnavailable, http.StatusOK)
+	resp, err := r.R().AddRetryCondition(Transient).Get("/jobs")
+	require.Nil(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+	require.Equal(t, 1, calls())
+
+	// Test a zero policy removes an earlier one
+	r, calls = newTestClient(t, testPolicy, "/jobs", http.StatusServiceUnavailable, http.StatusOK)
+	Policy{}.Apply(r)
+	_, err = r.R().Get("/jobs")
+	require.Nil(t, err)
+	require.Equal(t, 1, calls())
+}
+
+func TestPolicyStopsOnContextDone(t *testing.T) {
+	policy := Policy{MaxRetries: 5, WaitTime: time.Second, MaxWaitTime: time.Second}
+	r, calls := newTestClient(t, policy, "/jobs", http.StatusServiceUnavailable)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := r.R().SetContext(ctx).Get("/jobs")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 1, calls())
+}
+
+func TestRetryAfter(t *testing.T) {
+	response := func(statusCode int, value string) *resty.Response {
+		raw := &http.Response{StatusCode: statusCode, Header: http.Header{}}
+		raw.Header.Set("Retry-After", value)
+		return &resty.Response{RawResponse: raw}
+	}
+
+	wait, err := retryAfter(nil, response(http.StatusTooManyRequests, "2"))
+	require.Nil(t, err)
+	require.Equal(t, 2*time.Second, wait)
+
+	wait, err = retryAfter(nil, response(http.StatusServiceUnavailable, "7"))
+	require.Nil(t, err)
+	require.Equal(t, 7*time.Second, wait)
+
+	// Test Retry-After is ignored on other responses
+	wait, err = retryAfter(nil, response(http.StatusBadGateway, "7"))
+	require.Nil(t, err)
+	require.Zero(t, wait)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 4, 23, 16, 21, 0, 0, time.UTC)
+	require.Equal(t, 30*time.Second, ParseRetryAfter("30", now))
+	require.Equal(t, 90*time.Second, ParseRetryAfter("Thu, 23 Apr 2020 16:22:30 GMT", now))
+	require.Zero(t, ParseRetryAfter("Thu, 23 Apr 2020 16:20:00 GMT", now))
+	require.Zero(t, ParseRetryAfter("", now))
+	require.Zero(t, ParseRetryAfter("-1", now))
+	require.Zero(t, ParseRetryAfter("soon", now))
+}
+
+func TestIsTransientStatus(t *testing.T) {
+	for _, statusCode := range []int{408, 429, 502, 503, 504} {
+		require.True(t, IsTransientStatus(statusCode), statusCode)
+	}
+	for _, statusCode := range []int{200, 400, 401, 404, 409, 500} {
+		require.False(t, IsTransientStatus(statusCode), statusCode)
+	}
+}
@@ -0,0 +1,152 @@
+package tokenauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/puppetlabs/go-pe-client/pkg/apierror"
+	"github.com/puppetlabs/go-pe-client/pkg/rbac"
+)
+
+// Defaults of the RBACTokenSource timings
+const (
+	// DefaultLifetime is the lifetime PE gives a token when the request does not ask for one
+	DefaultLifetime = time.Hour
+	// DefaultRefreshBefore is how long before it expires a token is replaced
+	DefaultRefreshBefore = time.Minute
+	// DefaultCheckInterval is how often a token of unknown lifetime is checked with the RBAC API
+	DefaultCheckInterval = 5 * time.Minute
+)
+
+// RBACTokenSource fetches tokens from the RBAC API with a login and password and caches them until
+// shortly before they expire.
+// RefreshBefore	How long before its expiry a token is replaced.
+// CheckInterval	How often a token of unknown lifetime, such as one given to NewRBACTokenSource, is
+// authenticated with the RBAC API to find out whether it is still valid.
+type RBACTokenSource struct {
+	RefreshBefore time.Duration
+	CheckInterval time.Duration
+
+	client   *rbac.Client
+	keys     rbac.RequestKeys
+	lifetime time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expiry  time.Time
+	fetched bool
+}
+
+// NewRBACTokenSource creates a token source that logs in to RBAC with keys, requesting tokens with
+// keys.Lifetime. token is an existing token to use until it stops being valid, it may be empty.
+func NewRBACTokenSource(client *rbac.Client, keys rbac.RequestKeys, token string) (*RBACTokenSource, error) {
+	lifetime, err := parseLifetime(keys.Lifetime)
+	if err != nil {
+		return nil, err
+	}
+	return &RBACTokenSource{
+		RefreshBefore: DefaultRefreshBefore,
+		CheckInterval: DefaultCheckInterval,
+		client:        client,
+		keys:          keys,
+		lifetime:      lifetime,
+		now:           time.Now,
+		token:         token,
+	}, nil
+}
+
+// Token returns the cached token, replacing it first if it is about to expire
+func (s *RBACTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && !s.fetched && !s.fresh() {
+		// The token was given to NewRBACTokenSource, its lifetime is unknown so ask RBAC whether it is still valid
+		if err := s.check(ctx); err != nil {
+			return "", err
+		}
+	}
+	if s.token != "" && s.fresh() {
+		return s.token, nil
+	}
+	return s.fetch(ctx)
+}
+
+// Refresh fetches a new token if rejected is still the cached token
+func (s *RBACTokenSource) Refresh(ctx context.Context, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && s.token != rejected {
+		return s.token, nil
+	}
+	return s.fetch(ctx)
+}
+
+// fetch logs in to RBAC for a new token
+func (s *RBACTokenSource) fetch(ctx context.Context) (string, error) {
+	token, err := s.client.GetRBACTokenCtx(ctx, &s.keys)
+	if err != nil {
+		return "", err
+	}
+	s.token = token.Token
+	s.fetched = true
+	s.expiry = time.Time{}
+	if s.lifetime > 0 {
+		s.expiry = s.now().Add(s.lifetime)
+	}
+	return s.token, nil
+}
+
+// check authenticates a token of unknown lifetime, clearing it if RBAC no longer accepts it and otherwise
+// checking it again after CheckInterval
+func (s *RBACTokenSource) check(ctx context.Context) error {
+	_, err := s.client.AuthenticateRBACTokenCtx(ctx, s.token)
+	if status := apierror.StatusCode(err); status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+		s.token = ""
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.expiry = s.now().Add(s.CheckInterval + s.RefreshBefore)
+	return nil
+}
+
+// fresh reports whether the cached token can be used without fetching or checking it, a fetched token
+// without an expiry never expires
+func (s *RBACTokenSource) fresh() bool {
+	if s.expiry.IsZero() {
+		return s.fetched
+	}
+	return s.now().Before(s.expiry.Add(-s.RefreshBefore))
+}
+
+// parseLifetime parses an RBAC token lifetime, a number followed by one of the units y, d, h, m or s.
+// An empty lifetime is the PE default and 0 is a token that does not expire.
+func parseLifetime(lifetime string) (time.Duration, error) {
+	if lifetime == "" {
+		return DefaultLifetime, nil
+	}
+	if lifetime == "0" {
+		return 0, nil
+	}
+	units := map[byte]time.Duration{
+		'y': 365 * 24 * time.Hour,
+		'd': 24 * time.Hour,
+		'h': time.Hour,
+		'm': time.Minute,
+		's': time.Second,
+	}
+	unit, ok := units[lifetime[len(lifetime)-1]]
+	n, err := strconv.Atoi(lifetime[:len(lifetime)-1])
+	if !ok || err != nil || n < 0 {
+		return 0, fmt.Errorf("tokenauth: invalid token lifetime %q", lifetime)
+	}
+	return time.Duration(n) * unit, nil
+}
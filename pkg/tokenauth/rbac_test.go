@@ -0,0 +1,173 @@
+package tokenauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/puppetlabs/go-pe-client/pkg/rbac"
+	"github.com/stretchr/testify/require"
+)
+
+const rbacURL = "https://pe.example.com:4433"
+
+// rbacCalls counts the requests made to the mock RBAC API
+type rbacCalls struct {
+	fetches         int
+	authentications int
+}
+
+// newRBACTokenSource creates a token source for a mock RBAC API that issues the tokens token-1, token-2 and
+// so on and authenticates them along with the token "seeded". The source's clock is set from now.
+func newRBACTokenSource(t *testing.T, lifetime, token string, now *time.Time) (*RBACTokenSource, *rbacCalls) {
+	calls := &rbacCalls{}
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodPost, rbacURL+"/rbac-api/v1/auth/token",
+		func(req *http.Request) (*http.Response, error) {
+			calls.fetches++
+			return jsonResponse(http.StatusOK, fmt.Sprintf(`{"token": "token-%d"}`, calls.fetches)), nil
+		})
+	transport.RegisterResponder(http.MethodPost, rbacURL+"/rbac-api/v2/auth/token/authenticate",
+		func(req *http.Request) (*http.Response, error) {
+			calls.authentications++
+			var request rbac.AuthenticateRequest
+			if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+				return nil, err
+			}
+			if request.Token != "seeded" {
+				return jsonResponse(http.StatusUnauthorized, `{"kind": "puppetlabs.rbac/token-revoked", "msg": "revoked"}`), nil
+			}
+			return jsonResponse(http.StatusOK, `{"login": "admin"}`), nil
+		})
+
+	client := rbac.NewClient(rbacURL, nil)
+	client.SetTransport(transport)
+	source, err := NewRBACTokenSource(client, rbac.RequestKeys{Login: "admin", Password: "secret", Lifetime: lifetime}, token)
+	require.Nil(t, err)
+	source.now = func() time.Time { return *now }
+	return source, calls
+}
+
+func jsonResponse(status int, body string) *http.Response {
+	response := httpmock.NewStringResponse(status, body)
+	response.Header.Set("Content-Type", "application/json")
+	return response
+}
+
+func TestRBACTokenSource(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	source, calls := newRBACTokenSource(t, "10m", "", &now)
+
+	// Test the token is fetched once and cached
+	for i := 0; i < 2; i++ {
+		token, err := source.Token(ctx)
+		require.Nil(t, err)
+		require.Equal(t, "token-1", token)
+	}
+	require.Equal(t, 1, calls.fetches)
+
+	// Test the token is replaced shortly before it expires
+	now = now.Add(8 * time.Minute)
+	token, err := source.Token(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "token-1", token)
+	now = now.Add(90 * time.Second)
+	token, err = source.Token(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "token-2", token)
+
+	// Test refreshing a rejected token
+	token, err = source.Refresh(ctx, "token-2")
+	require.Nil(t, err)
+	require.Equal(t, "token-3", token)
+
+	// Test a token that was already replaced is not refreshed again
+	token, err = source.Refresh(ctx, "token-2")
+	require.Nil(t, err)
+	require.Equal(t, "token-3", token)
+	require.Equal(t, 3, calls.fetches)
+	require.Equal(t, 0, calls.authentications)
+}
+
+func TestRBACTokenSourceWithoutExpiry(t *testing.T) {
+	now := time.Now()
+	source, calls := newRBACTokenSource(t, "0", "", &now)
+
+	_, err := source.Token(context.Background())
+	require.Nil(t, err)
+	now = now.Add(24 * 365 * time.Hour)
+	token, err := source.Token(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, "token-1", token)
+	require.Equal(t, 1, calls.fetches)
+}
+
+func TestRBACTokenSourceSeeded(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	source, calls := newRBACTokenSource(t, "", "seeded", &now)
+
+	// Test the seeded token is authenticated and then used until the next check
+	for i := 0; i < 2; i++ {
+		token, err := source.Token(ctx)
+		require.Nil(t, err)
+		require.Equal(t, "seeded", token)
+	}
+	require.Equal(t, 1, calls.authentications)
+
+	now = now.Add(DefaultCheckInterval)
+	token, err := source.Token(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "seeded", token)
+	require.Equal(t, 2, calls.authentications)
+	require.Equal(t, 0, calls.fetches)
+
+	// Test a seeded token that is no longer valid is replaced
+	source, calls = newRBACTokenSource(t, "", "revoked", &now)
+	token, err = source.Token(ctx)
+	require.Nil(t, err)
+	require.Equal(t, "token-1", token)
+	require.Equal(t, 1, calls.authentications)
+	require.Equal(t, 1, calls.fetches)
+}
+
+func TestRBACTokenSourceError(t *testing.T) {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodPost, rbacURL+"/rbac-api/v1/auth/token",
+		httpmock.ResponderFromResponse(jsonResponse(http.StatusUnauthorized, `{"kind": "puppetlabs.rbac/authentication-failed", "msg": "bad login"}`)))
+	client := rbac.NewClient(rbacURL, nil)
+	client.SetTransport(transport)
+
+	source, err := NewRBACTokenSource(client, rbac.RequestKeys{Login: "admin", Password: "wrong"}, "")
+	require.Nil(t, err)
+	_, err = source.Token(context.Background())
+	require.EqualError(t, err, "bad login")
+
+	_, err = NewRBACTokenSource(client, rbac.RequestKeys{Lifetime: "1w"}, "")
+	require.EqualError(t, err, `tokenauth: invalid token lifetime "1w"`)
+}
+
+func TestParseLifetime(t *testing.T) {
+	for lifetime, expected := range map[string]time.Duration{
+		"":    DefaultLifetime,
+		"0":   0,
+		"30s": 30 * time.Second,
+		"15m": 15 * time.Minute,
+		"2h":  2 * time.Hour,
+		"7d":  7 * 24 * time.Hour,
+		"1y":  365 * 24 * time.Hour,
+	} {
+		actual, err := parseLifetime(lifetime)
+		require.Nil(t, err, lifetime)
+		require.Equal(t, expected, actual, lifetime)
+	}
+	for _, lifetime := range []string{"h", "-1h", "1.5h", "10"} {
+		_, err := parseLifetime(lifetime)
+		require.NotNil(t, err, lifetime)
+	}
+}
@@ -0,0 +1,96 @@
+// Package tokenauth supplies the RBAC tokens of the PE API clients per request, so that long running
+// services keep working when their token expires or is revoked, e.g.
+//
+//	source, err := tokenauth.NewRBACTokenSource(rbacClient, rbac.RequestKeys{Login: user, Password: password}, "")
+//	orchClient := orch.NewClient("https://pe.example.com:8143", "", tlsConfig)
+//	orchClient.SetTokenSource(source)
+package tokenauth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// TokenSource supplies the token sent in the X-Authentication header of each request
+type TokenSource interface {
+	// Token returns the token to use, fetching a new one if there is no valid token cached
+	Token(ctx context.Context) (string, error)
+	// Refresh returns a token to replace rejected, which the API refused. If the source has already
+	// replaced rejected it returns the replacement rather than fetching another token.
+	Refresh(ctx context.Context, rejected string) (string, error)
+}
+
+// StaticTokenSource always supplies the same token
+type StaticTokenSource string
+
+// Token returns the token
+func (s StaticTokenSource) Token(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// Refresh returns the token, there is no other to replace it with
+func (s StaticTokenSource) Refresh(context.Context, string) (string, error) {
+	return string(s), nil
+}
+
+// Transport sets the X-Authentication header of each request from a TokenSource. If a request is
+// rejected with a 401 it is sent once more with a refreshed token.
+type Transport struct {
+	Source TokenSource
+	Base   http.RoundTripper
+}
+
+// NewTransport wraps base, or http.DefaultTransport if base is nil, to authenticate with the tokens of
+// source. If base is already a Transport its token source is replaced rather than wrapping it again.
+func NewTransport(base http.RoundTripper, source TokenSource) *Transport {
+	if t, ok := base.(*Transport); ok {
+		base = t.Base
+	}
+	return &Transport{Source: source, Base: base}
+}
+
+// RoundTrip sends the request with the current token, retrying once with a new token on a 401
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Source.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("tokenauth: %w", err)
+	}
+	resp, err := t.base().RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The body of the first attempt has been read, only retry if it can be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	refreshed, err := t.Source.Refresh(req.Context(), token)
+	if err != nil || refreshed == token {
+		return resp, nil
+	}
+	retry := withToken(req, refreshed)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return t.base().RoundTrip(retry)
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+// withToken returns a copy of req authenticated with token, a RoundTripper must not modify its request
+func withToken(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("X-Authentication", token)
+	return clone
+}
@@ -0,0 +1,108 @@
+package tokenauth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/require"
+)
+
+// testSource supplies token until it is refreshed, then refreshed
+type testSource struct {
+	token     string
+	refreshed string
+	refreshes int
+	err       error
+}
+
+func (s *testSource) Token(context.Context) (string, error) {
+	return s.token, s.err
+}
+
+func (s *testSource) Refresh(_ context.Context, rejected string) (string, error) {
+	s.refreshes++
+	if rejected == s.token {
+		s.token = s.refreshed
+	}
+	return s.token, nil
+}
+
+// newAuthenticatingTransport accepts requests with the token "good", echoing the request body
+func newAuthenticatingTransport() *httpmock.MockTransport {
+	transport := httpmock.NewMockTransport()
+	transport.RegisterResponder(http.MethodPost, "https://pe.example.com/api",
+		func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("X-Authentication") != "good" {
+				return httpmock.NewStringResponse(http.StatusUnauthorized, `{"kind": "puppetlabs.rbac/token-expired"}`), nil
+			}
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			return httpmock.NewBytesResponse(http.StatusOK, body), nil
+		})
+	return transport
+}
+
+func post(t *testing.T, transport http.RoundTripper, body string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, "https://pe.example.com/api", strings.NewReader(body))
+	require.Nil(t, err)
+	req.Header.Set("X-Authentication", "from-client")
+	resp, err := transport.RoundTrip(req)
+	require.Nil(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, "from-client", req.Header.Get("X-Authentication"))
+	return resp
+}
+
+func TestTransport(t *testing.T) {
+	base := newAuthenticatingTransport()
+
+	// Test the token is sent
+	source := &testSource{token: "good"}
+	resp := post(t, NewTransport(base, source), "payload")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 0, source.refreshes)
+
+	// Test a 401 is retried once with a refreshed token and the body sent again
+	source = &testSource{token: "expired", refreshed: "good"}
+	resp = post(t, NewTransport(base, source), "payload")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+	require.Equal(t, "payload", string(body))
+	require.Equal(t, 1, source.refreshes)
+	require.Equal(t, 3, base.GetTotalCallCount())
+
+	// Test the 401 is returned when the refreshed token is also rejected
+	source = &testSource{token: "expired", refreshed: "revoked"}
+	resp = post(t, NewTransport(base, source), "payload")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Equal(t, 1, source.refreshes)
+	require.Equal(t, 5, base.GetTotalCallCount())
+
+	// Test a static token is not sent again
+	resp = post(t, NewTransport(base, StaticTokenSource("expired")), "payload")
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Equal(t, 6, base.GetTotalCallCount())
+
+	// Test token error
+	req, err := http.NewRequest(http.MethodPost, "https://pe.example.com/api", nil)
+	require.Nil(t, err)
+	_, err = NewTransport(base, &testSource{err: errors.New("rbac is down")}).RoundTrip(req)
+	require.EqualError(t, err, "tokenauth: rbac is down")
+}
+
+func TestNewTransport(t *testing.T) {
+	base := httpmock.NewMockTransport()
+	transport := NewTransport(NewTransport(base, StaticTokenSource("a")), StaticTokenSource("b"))
+	require.Equal(t, base, transport.Base)
+	require.Equal(t, StaticTokenSource("b"), transport.Source)
+
+	require.Equal(t, http.DefaultTransport, NewTransport(nil, StaticTokenSource("a")).base())
+}